@@ -1 +1,8 @@
+# By GlobalID
 terraform import ocp_virtual_host.example "<VirtualHost GlobalID>"
+
+# By hostname, optionally narrowed to a project
+terraform import ocp_virtual_host.example "hostname=app-01,project=my-project"
+
+# By VM UUID
+terraform import ocp_virtual_host.example "uuid=4210a1b2-3c4d-5e6f-7a8b-9c0d1e2f3a4b"
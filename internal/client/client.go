@@ -2,12 +2,26 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// DefaultRequestTimeout is the per-request timeout used when none is configured.
+const DefaultRequestTimeout = 60 * time.Second
+
+// maxResponseBytes limits how much of a response body is read into memory.
+const maxResponseBytes = 32 << 20
+
 // ConfigureContextFunc initializes the API client once and stores it in `meta`.
 // All resources and data sources retrieve it via `meta.(*client.Client)`.
 //
@@ -15,23 +29,62 @@ import (
 type Client struct {
 	endpoint string
 	token    string
-	http     *http.Client
+	timeout  time.Duration
+	retry    RetryPolicy
+
+	// limiter throttles all HTTP requests; mutations bounds concurrent mutations.
+	// Both are nil when disabled.
+	limiter   *tokenBucket
+	mutations chan struct{}
+
+	// pollInterval is the delay between status queries in Poll and WaitForTask.
+	pollInterval time.Duration
+
+	transport *http.Transport
+	http      *http.Client
+}
+
+// Option customizes a Client created by New.
+type Option func(*Client)
+
+// WithRequestTimeout sets the maximum duration of a single API request.
+// A zero or negative value disables the per-request timeout; the caller's
+// context still applies.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
 }
 
 // New creates a Client configured for the given endpoint and token.
 // If insecure is true, TLS certificate verification is skipped.
-func New(endpoint, token string, insecure bool) *Client {
+func New(endpoint, token string, insecure bool, opts ...Option) *Client {
 	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: insecure, // When true, skip TLS certificate verification.
 		},
 	}
 
-	return &Client{
+	c := &Client{
 		endpoint: endpoint,
 		token:    token,
-		http:     &http.Client{Transport: transport},
+		timeout:  DefaultRequestTimeout,
+		retry: RetryPolicy{
+			MaxRetries: DefaultMaxRetries,
+			MinWait:    DefaultRetryMinWait,
+			MaxWait:    DefaultRetryMaxWait,
+		},
+		pollInterval: DefaultPollInterval,
+		transport:    transport,
+		http:         &http.Client{Transport: transport},
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
 // gqlRequest is the JSON envelope for GraphQL requests.
@@ -42,17 +95,31 @@ type gqlRequest struct {
 
 // gqlResponse is the JSON envelope for GraphQL responses.
 type gqlResponse struct {
-	Data   json.RawMessage `json:"data"`
-	Errors []struct {
-		Message string `json:"message"`
-	} `json:"errors"`
+	Data   json.RawMessage     `json:"data"`
+	Errors []GraphQLErrorEntry `json:"errors"`
 }
 
-// Do executes a GraphQL query or mutation and unmarshals the "data" field into `into`.
+// Do executes a GraphQL query or mutation without a caller context.
 //
-// If the response contains GraphQL errors, the first error is returned as Go error.
-// If `into` is nil, the "data" payload is ignored (useful for mutations where only success matters).
+// Deprecated: use DoContext so that requests are cancelled together with the
+// Terraform operation that issued them.
 func (c *Client) Do(query string, variables map[string]interface{}, into interface{}) error {
+	return c.DoContext(context.Background(), query, variables, into)
+}
+
+// DoContext executes a GraphQL query or mutation and unmarshals the "data" field into `into`.
+//
+// Each attempt is bound to ctx and to the client's per-request timeout, whichever
+// expires first. Timeouts and cancellations are reported as errors wrapping
+// context.DeadlineExceeded or context.Canceled respectively.
+//
+// Transient failures are retried according to the client's RetryPolicy. Every
+// attempt waits for the client-side rate limit, and mutations additionally wait
+// for a free slot when WithMaxConcurrentMutations is set.
+//
+// If the response contains GraphQL errors, they are returned as *GraphQLError.
+// If `into` is nil, the "data" payload is ignored (useful for mutations where only success matters).
+func (c *Client) DoContext(ctx context.Context, query string, variables map[string]interface{}, into interface{}) error {
 	reqBody, err := json.Marshal(gqlRequest{
 		Query:     query,
 		Variables: variables,
@@ -61,32 +128,199 @@ func (c *Client) Do(query string, variables map[string]interface{}, into interfa
 		return err
 	}
 
-	req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(reqBody))
+	op := parseOperation(query)
+	ctx = c.withLogging(ctx)
+	tflog.SubsystemTrace(ctx, logSubsystem, "GraphQL request variables", map[string]interface{}{
+		"operation": op.name,
+		"variables": redactVariables(variables),
+	})
+
+	if op.mutation() {
+		release, err := c.acquireMutationSlot(ctx)
+		if err != nil {
+			return c.contextError(ctx, ctx, err)
+		}
+		defer release()
+	}
+
+	var gqlResp *gqlResponse
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			delay, err := c.limiter.wait(ctx)
+			if err != nil {
+				return c.contextError(ctx, ctx, err)
+			}
+			if delay > 0 {
+				tflog.SubsystemDebug(ctx, logSubsystem, "GraphQL request delayed by client-side rate limit", map[string]interface{}{
+					"operation": op.name,
+					"wait_ms":   delay.Milliseconds(),
+				})
+			}
+		}
+
+		gqlResp, err = c.send(ctx, reqBody, op, attempt+1)
+		if err == nil {
+			break
+		}
+
+		var rerr *retryableError
+		if !errors.As(err, &rerr) {
+			return err
+		}
+		if attempt >= c.retry.MaxRetries {
+			if attempt == 0 {
+				return rerr.err
+			}
+			return fmt.Errorf("giving up after %d attempts: %w", attempt+1, rerr.err)
+		}
+
+		wait := c.retry.backoff(attempt, rerr.after)
+		tflog.SubsystemDebug(ctx, logSubsystem, "retrying GraphQL request", map[string]interface{}{
+			"operation": op.name,
+			"attempt":   attempt + 1,
+			"wait_ms":   wait.Milliseconds(),
+			"error":     rerr.Error(),
+		})
+
+		if err := sleep(ctx, wait); err != nil {
+			return c.contextError(ctx, ctx, err)
+		}
+	}
+
+	if into != nil {
+		return json.Unmarshal(gqlResp.Data, into)
+	}
+
+	return nil
+}
+
+// send performs a single HTTP attempt. Failures that may be retried are
+// returned as *retryableError.
+func (c *Client) send(ctx context.Context, reqBody []byte, op operation, attempt int) (*gqlResponse, error) {
+	mutation := op.mutation()
+	logFields := map[string]interface{}{
+		"operation":      op.name,
+		"operation_type": op.kind,
+		"attempt":        attempt,
+	}
+	reqCtx := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	// Track whether the request was written to the connection. If it wasn't,
+	// the server never saw it and even a mutation is safe to resend.
+	var wrote atomic.Bool
+	reqCtx = httptrace.WithClientTrace(reqCtx, &httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			wrote.Store(true)
+		},
+	})
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, c.endpoint, bytes.NewReader(reqBody))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Auth-Token", c.token)
 
+	tflog.SubsystemDebug(ctx, logSubsystem, "sending GraphQL request", logFields)
+	tflog.SubsystemTrace(ctx, logSubsystem, "GraphQL request headers", map[string]interface{}{
+		"operation": op.name,
+		"headers": map[string]interface{}{
+			"Content-Type": req.Header.Get("Content-Type"),
+			"X-Auth-Token": redacted,
+		},
+	})
+
+	start := time.Now()
 	resp, err := c.http.Do(req)
+	logFields["duration_ms"] = time.Since(start).Milliseconds()
 	if err != nil {
-		return err
+		err = c.contextError(ctx, reqCtx, err)
+		logFields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "GraphQL request failed", logFields)
+		if ctx.Err() != nil || isPermanentTransportError(err) {
+			return nil, err
+		}
+		if !mutation || !wrote.Load() {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	logFields["duration_ms"] = time.Since(start).Milliseconds()
+	logFields["status"] = resp.StatusCode
+	tflog.SubsystemDebug(ctx, logSubsystem, "received GraphQL response", logFields)
+	tflog.SubsystemTrace(ctx, logSubsystem, "GraphQL response body", map[string]interface{}{
+		"operation": op.name,
+		"body":      snippet(body),
+	})
+	if err != nil {
+		err = c.contextError(ctx, reqCtx, err)
+		// A body cut short by a broken connection or the per-request timeout
+		// is transient.
+		truncated := errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded)
+		if !mutation && ctx.Err() == nil && truncated {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Some GraphQL servers report request-level errors with a non-2xx status
+		// and a regular JSON error payload; keep those reachable as a GraphQLError
+		// inside the HTTPError so that the status still drives retries and
+		// error classification.
+		var gqlErr error
+		var gqlResp gqlResponse
+		if json.Unmarshal(body, &gqlResp) == nil && len(gqlResp.Errors) > 0 {
+			gqlErr = &GraphQLError{Errors: gqlResp.Errors}
+		}
+
+		httpErr := newHTTPError(resp, body, gqlErr)
+		if !mutation && isRetryableStatus(resp.StatusCode) {
+			return nil, &retryableError{
+				err:   httpErr,
+				after: parseRetryAfter(resp.Header.Get("Retry-After")),
+			}
+		}
+		return nil, httpErr
+	}
+
 	var gqlResp gqlResponse
-	if err := json.NewDecoder(resp.Body).Decode(&gqlResp); err != nil {
-		return err
+	if err := json.Unmarshal(body, &gqlResp); err != nil {
+		return nil, newHTTPError(resp, body, err)
 	}
 
 	if len(gqlResp.Errors) > 0 {
-		return fmt.Errorf("graphql error: %s", gqlResp.Errors[0].Message)
+		gqlErr := &GraphQLError{Errors: gqlResp.Errors}
+		if !mutation && IsRateLimited(gqlErr) {
+			return nil, &retryableError{err: gqlErr}
+		}
+		return nil, gqlErr
 	}
 
-	if into != nil {
-		return json.Unmarshal(gqlResp.Data, into)
-	}
+	return &gqlResp, nil
+}
 
-	return nil
+// contextError translates a transport error caused by context expiry into an
+// error that states whether the request timed out or was cancelled.
+// Errors unrelated to the contexts are returned unchanged.
+func (c *Client) contextError(ctx, reqCtx context.Context, err error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("request cancelled: %w", context.Canceled)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("request aborted, operation deadline exceeded: %w", context.DeadlineExceeded)
+	case errors.Is(reqCtx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("request timed out after %s: %w", c.timeout, context.DeadlineExceeded)
+	default:
+		return err
+	}
 }
@@ -0,0 +1,476 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientDoContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Auth-Token"); got != "token" {
+			t.Fatalf("expected X-Auth-Token token, got %q", got)
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"ping": "pong",
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true)
+
+	var resp struct {
+		Ping string `json:"ping"`
+	}
+	if err := client.DoContext(context.Background(), "query { ping }", nil, &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Ping != "pong" {
+		t.Fatalf("expected pong, got %q", resp.Ping)
+	}
+}
+
+func TestClientDoContextTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := New(server.URL, "token", true, WithRequestTimeout(50*time.Millisecond), WithRetryPolicy(RetryPolicy{}))
+
+	err := client.DoContext(context.Background(), "query { ping }", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Fatalf("expected timeout message, got %q", err.Error())
+	}
+}
+
+func TestClientDoContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := New(server.URL, "token", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	err := client.DoContext(ctx, "query { ping }", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "cancelled") {
+		t.Fatalf("expected cancellation message, got %q", err.Error())
+	}
+}
+
+func TestClientDoContextRetriesQuery(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"ping": "pong",
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true, WithRetryPolicy(RetryPolicy{
+		MaxRetries: 3,
+		MinWait:    time.Millisecond,
+		MaxWait:    10 * time.Millisecond,
+	}))
+
+	if err := client.DoContext(context.Background(), "query { ping }", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestClientDoContextRetriesJSONErrorStatus(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"errors":[{"message":"slow down"}]}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true, WithRetryPolicy(RetryPolicy{
+		MaxRetries: 3,
+		MinWait:    time.Millisecond,
+		MaxWait:    10 * time.Millisecond,
+	}))
+
+	err := client.DoContext(context.Background(), "query { ping }", nil, nil)
+	if calls != 4 {
+		t.Fatalf("expected 4 attempts, got %d", calls)
+	}
+	if !IsRateLimited(err) {
+		t.Fatalf("expected IsRateLimited to be true, got %v", err)
+	}
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) || gqlErr.Errors[0].Message != "slow down" {
+		t.Fatalf("expected the GraphQL error to be kept, got %v", err)
+	}
+}
+
+func TestClientDoContextDoesNotRetryDeliveredMutation(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true, WithRetryPolicy(RetryPolicy{
+		MaxRetries: 3,
+		MinWait:    time.Millisecond,
+		MaxWait:    10 * time.Millisecond,
+	}))
+
+	if err := client.DoContext(context.Background(), "mutation { ping }", nil, nil); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestClientDoContextRetriesUndeliveredMutation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := server.URL
+	server.Close()
+
+	client := New(endpoint, "token", true, WithRetryPolicy(RetryPolicy{
+		MaxRetries: 2,
+		MinWait:    time.Millisecond,
+		MaxWait:    10 * time.Millisecond,
+	}))
+
+	err := client.DoContext(context.Background(), "mutation { ping }", nil, nil)
+	if err == nil {
+		t.Fatalf("expected error, got none")
+	}
+	if !strings.Contains(err.Error(), "giving up after 3 attempts") {
+		t.Fatalf("expected retries to be exhausted, got %q", err.Error())
+	}
+}
+
+func TestClientDoContextDoesNotRetryPermanentTransportErrors(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("request must not reach the handler")
+	}))
+	defer server.Close()
+
+	// The test server's certificate is not trusted without insecure.
+	client := New(server.URL, "token", false, WithRetryPolicy(RetryPolicy{MaxRetries: 3, MinWait: time.Second, MaxWait: time.Second}))
+
+	start := time.Now()
+	err := client.DoContext(context.Background(), "query { ping }", nil, nil)
+	if err == nil {
+		t.Fatalf("expected certificate error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected no retries, took %s: %v", elapsed, err)
+	}
+}
+
+func TestIsPermanentTransportError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "unknown authority", err: fmt.Errorf("post: %w", x509.UnknownAuthorityError{}), want: true},
+		{name: "no such host", err: &net.DNSError{Err: "no such host", Name: "ocp.invalid", IsNotFound: true}, want: true},
+		{name: "temporary dns failure", err: &net.DNSError{Err: "server misbehaving", Name: "ocp.example", IsTemporary: true}, want: false},
+		{name: "connection reset", err: errors.New("connection reset by peer"), want: false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isPermanentTransportError(tc.err); got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinWait: time.Second, MaxWait: 5 * time.Second}
+
+	for attempt := 0; attempt < 6; attempt++ {
+		got := policy.backoff(attempt, 0)
+		if got < 500*time.Millisecond || got > 5*time.Second {
+			t.Fatalf("attempt %d: backoff %s out of range", attempt, got)
+		}
+	}
+	if got := policy.backoff(0, time.Minute); got != 5*time.Second {
+		t.Fatalf("expected Retry-After to be capped at 5s, got %s", got)
+	}
+	if got := parseRetryAfter("7"); got != 7*time.Second {
+		t.Fatalf("expected 7s, got %s", got)
+	}
+}
+
+func TestClientDoContextGraphQLError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": nil,
+			"errors": []interface{}{
+				map[string]interface{}{
+					"message":   "VirtualHost matching query does not exist.",
+					"path":      []interface{}{"virtualHost"},
+					"locations": []interface{}{map[string]interface{}{"line": 2, "column": 3}},
+				},
+				map[string]interface{}{
+					"message":    "Token expired",
+					"extensions": map[string]interface{}{"code": "UNAUTHENTICATED"},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true)
+
+	err := client.DoContext(context.Background(), "query { virtualHost }", nil, nil)
+
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) {
+		t.Fatalf("expected *GraphQLError, got %T: %v", err, err)
+	}
+	if len(gqlErr.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(gqlErr.Errors))
+	}
+	if got := gqlErr.Errors[0].Locations[0].Line; got != 2 {
+		t.Fatalf("expected location line 2, got %d", got)
+	}
+	if got := gqlErr.Errors[1].Code(); got != "UNAUTHENTICATED" {
+		t.Fatalf("expected code UNAUTHENTICATED, got %q", got)
+	}
+	if !strings.Contains(err.Error(), "(path: virtualHost)") || !strings.Contains(err.Error(), "Token expired") {
+		t.Fatalf("expected all errors in message, got %q", err.Error())
+	}
+	if !IsNotFound(err) {
+		t.Fatalf("expected IsNotFound to be true")
+	}
+	if !IsUnauthorized(err) {
+		t.Fatalf("expected IsUnauthorized to be true")
+	}
+	if IsRateLimited(err) {
+		t.Fatalf("expected IsRateLimited to be false")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	testCases := []struct {
+		name  string
+		entry GraphQLErrorEntry
+		want  bool
+	}{
+		{
+			name:  "root field message",
+			entry: GraphQLErrorEntry{Message: "VirtualHost matching query does not exist.", Path: []interface{}{"virtualHost"}},
+			want:  true,
+		},
+		{
+			name:  "nested field message",
+			entry: GraphQLErrorEntry{Message: "DataProtectionPolicy matching query does not exist.", Path: []interface{}{"virtualHost", "dataProtectionPolicy"}},
+			want:  false,
+		},
+		{
+			name: "nested field code",
+			entry: GraphQLErrorEntry{
+				Message:    "Tier not found",
+				Path:       []interface{}{"virtualHost", "tier"},
+				Extensions: map[string]interface{}{"code": "NOT_FOUND"},
+			},
+			want: false,
+		},
+		{
+			name:  "code without path",
+			entry: GraphQLErrorEntry{Message: "gone", Extensions: map[string]interface{}{"code": "NOT_FOUND"}},
+			want:  true,
+		},
+		{
+			name:  "message without path",
+			entry: GraphQLErrorEntry{Message: "object does not exist"},
+			want:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := &GraphQLError{Errors: []GraphQLErrorEntry{tc.entry}}
+			if got := IsNotFound(err); got != tc.want {
+				t.Fatalf("expected IsNotFound %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIsQueryRejected(t *testing.T) {
+	testCases := []struct {
+		name  string
+		entry GraphQLErrorEntry
+		want  bool
+	}{
+		{
+			name:  "unknown field",
+			entry: GraphQLErrorEntry{Message: "Cannot query field 'minIops' on type 'TierNode'."},
+			want:  true,
+		},
+		{
+			name:  "not found",
+			entry: GraphQLErrorEntry{Message: "Template matching query does not exist.", Path: []interface{}{"template"}},
+			want:  false,
+		},
+		{
+			name:  "not found code without path",
+			entry: GraphQLErrorEntry{Message: "gone", Extensions: map[string]interface{}{"code": "NOT_FOUND"}},
+			want:  false,
+		},
+		{
+			name:  "unauthenticated",
+			entry: GraphQLErrorEntry{Message: "Authentication required", Extensions: map[string]interface{}{"code": "UNAUTHENTICATED"}},
+			want:  false,
+		},
+		{
+			name:  "field resolver error",
+			entry: GraphQLErrorEntry{Message: "internal error", Path: []interface{}{"tier", "maxIops"}},
+			want:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := &GraphQLError{Errors: []GraphQLErrorEntry{tc.entry}}
+			if got := IsQueryRejected(err); got != tc.want {
+				t.Fatalf("expected IsQueryRejected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClientDoContextHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("<html>\n  <body>Please log in</body>\n</html>"))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true)
+
+	err := client.DoContext(context.Background(), "query { ping }", nil, nil)
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected *HTTPError, got %T: %v", err, err)
+	}
+	if httpErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", httpErr.StatusCode)
+	}
+	if httpErr.RequestID != "req-1" {
+		t.Fatalf("expected request id req-1, got %q", httpErr.RequestID)
+	}
+	if httpErr.Body != "<html> <body>Please log in</body> </html>" {
+		t.Fatalf("unexpected body snippet %q", httpErr.Body)
+	}
+	if !IsUnauthorized(err) {
+		t.Fatalf("expected IsUnauthorized to be true")
+	}
+}
+
+func TestClientDoContextJSONErrorKeepsStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":[{"message":"permission check failed"}]}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true)
+
+	err := client.DoContext(context.Background(), "query { ping }", nil, nil)
+	if httpStatus(err) != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %v", err)
+	}
+	if !IsUnauthorized(err) {
+		t.Fatalf("expected IsUnauthorized to be true")
+	}
+	if err.Error() != "HTTP 403 Forbidden: graphql error: permission check failed" {
+		t.Fatalf("unexpected message: %q", err.Error())
+	}
+}
+
+func TestClientDoContextNonJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html>maintenance</html>"))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true)
+
+	err := client.DoContext(context.Background(), "query { ping }", nil, nil)
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected *HTTPError, got %T: %v", err, err)
+	}
+	if !strings.Contains(err.Error(), "unexpected non-JSON response") {
+		t.Fatalf("expected non-JSON message, got %q", err.Error())
+	}
+}
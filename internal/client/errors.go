@@ -0,0 +1,166 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GraphQLErrorLocation points to a position in the GraphQL document.
+type GraphQLErrorLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLErrorEntry is a single entry of the "errors" array of a GraphQL response.
+type GraphQLErrorEntry struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Locations  []GraphQLErrorLocation `json:"locations,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Code returns the "code" extension of the entry, or an empty string if absent.
+func (e GraphQLErrorEntry) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// String formats the entry as "message (path: a.b.c)".
+func (e GraphQLErrorEntry) String() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	parts := make([]string, 0, len(e.Path))
+	for _, p := range e.Path {
+		parts = append(parts, fmt.Sprint(p))
+	}
+	return fmt.Sprintf("%s (path: %s)", e.Message, strings.Join(parts, "."))
+}
+
+// GraphQLError is returned when the API responds with a non-empty "errors" array.
+// It keeps every error entry; use errors.As to inspect it.
+type GraphQLError struct {
+	Errors []GraphQLErrorEntry
+}
+
+// Error implements the error interface.
+func (e *GraphQLError) Error() string {
+	if len(e.Errors) == 0 {
+		return "graphql error"
+	}
+	msgs := make([]string, 0, len(e.Errors))
+	for _, entry := range e.Errors {
+		msgs = append(msgs, entry.String())
+	}
+	return "graphql error: " + strings.Join(msgs, "; ")
+}
+
+// has reports whether any entry matches one of the codes or contains one of the message fragments.
+func (e *GraphQLError) has(codes []string, fragments []string) bool {
+	for _, entry := range e.Errors {
+		code := strings.ToUpper(entry.Code())
+		for _, c := range codes {
+			if code == c {
+				return true
+			}
+		}
+		msg := strings.ToLower(entry.Message)
+		for _, f := range fragments {
+			if strings.Contains(msg, f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var (
+	notFoundCodes     = []string{"NOT_FOUND", "DOES_NOT_EXIST"}
+	notFoundFragments = []string{"not found", "does not exist", "could not be resolved"}
+
+	unauthorizedCodes     = []string{"UNAUTHENTICATED", "UNAUTHORIZED", "FORBIDDEN", "PERMISSION_DENIED"}
+	unauthorizedFragments = []string{"not authenticated", "unauthorized", "permission denied", "token expired", "invalid token"}
+
+	rateLimitedCodes     = []string{"RATE_LIMITED", "TOO_MANY_REQUESTS", "THROTTLED"}
+	rateLimitedFragments = []string{"rate limit", "too many requests", "throttl"}
+)
+
+// notFound reports whether the entry states that the queried root object doesn't exist.
+// Entries for nested fields, e.g. ["virtualHost", "dataProtectionPolicy"], never count: the
+// root object exists, only something it references is missing. A message alone is only
+// trusted when the path is exactly the root field; an entry without a path needs an explicit code.
+func (e GraphQLErrorEntry) notFound() bool {
+	if len(e.Path) > 1 {
+		return false
+	}
+	code := strings.ToUpper(e.Code())
+	for _, c := range notFoundCodes {
+		if code == c {
+			return true
+		}
+	}
+	if len(e.Path) != 1 {
+		return false
+	}
+	msg := strings.ToLower(e.Message)
+	for _, f := range notFoundFragments {
+		if strings.Contains(msg, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is a GraphQL error stating that the requested root object
+// doesn't exist (see GraphQLErrorEntry.notFound). An HTTP 404 is deliberately not treated as
+// "not found": it means the endpoint is wrong, not that the object is gone.
+func IsNotFound(err error) bool {
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) {
+		return false
+	}
+	for _, entry := range gqlErr.Errors {
+		if entry.notFound() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsQueryRejected reports whether err is a GraphQL error raised while validating the query
+// document, for example because it selects a field the schema doesn't define. Such errors
+// carry no path, unlike errors raised while resolving a field (not found, permission denied).
+func IsQueryRejected(err error) bool {
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) || len(gqlErr.Errors) == 0 ||
+		IsNotFound(err) || IsUnauthorized(err) || IsRateLimited(err) {
+		return false
+	}
+	for _, entry := range gqlErr.Errors {
+		if len(entry.Path) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsUnauthorized reports whether err is caused by missing, expired or rejected credentials.
+// Unauthorized mutation payloads are permission errors; see IsPermissionDenied.
+func IsUnauthorized(err error) bool {
+	if code := httpStatus(err); code == http.StatusUnauthorized || code == http.StatusForbidden {
+		return true
+	}
+	var gqlErr *GraphQLError
+	return errors.As(err, &gqlErr) && gqlErr.has(unauthorizedCodes, unauthorizedFragments)
+}
+
+// IsRateLimited reports whether err is caused by the API throttling the client.
+func IsRateLimited(err error) bool {
+	if httpStatus(err) == http.StatusTooManyRequests {
+		return true
+	}
+	var gqlErr *GraphQLError
+	return errors.As(err, &gqlErr) && gqlErr.has(rateLimitedCodes, rateLimitedFragments)
+}
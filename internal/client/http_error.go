@@ -0,0 +1,99 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxSnippetBytes limits the size of the response body kept in HTTPError.
+const maxSnippetBytes = 512
+
+// requestIDHeaders lists response headers that may carry a request/correlation ID.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"}
+
+// HTTPError is returned when the API responds with a non-2xx status or with a
+// body that isn't a GraphQL JSON document (e.g. an HTML page from a proxy).
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Status is the HTTP status line, e.g. "503 Service Unavailable".
+	Status string
+	// ContentType is the Content-Type header of the response.
+	ContentType string
+	// Body is a truncated, whitespace-collapsed snippet of the response body.
+	Body string
+	// RequestID is the request/correlation ID reported by the server, if any.
+	RequestID string
+	// Err is the decoding error for 2xx responses with a non-JSON body, or the
+	// *GraphQLError carried by a non-2xx response with a JSON error payload.
+	Err error
+}
+
+func newHTTPError(resp *http.Response, body []byte, err error) *HTTPError {
+	e := &HTTPError{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        snippet(body),
+		Err:         err,
+	}
+	for _, h := range requestIDHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			e.RequestID = v
+			break
+		}
+	}
+	return e
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	var b strings.Builder
+
+	if e.StatusCode >= 200 && e.StatusCode <= 299 {
+		fmt.Fprintf(&b, "unexpected non-JSON response (HTTP %s", e.Status)
+		if e.ContentType != "" {
+			fmt.Fprintf(&b, ", content-type %s", e.ContentType)
+		}
+		b.WriteString(")")
+	} else {
+		fmt.Fprintf(&b, "HTTP %s", e.Status)
+	}
+
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request id %s]", e.RequestID)
+	}
+
+	var gqlErr *GraphQLError
+	switch {
+	case errors.As(e.Err, &gqlErr):
+		fmt.Fprintf(&b, ": %s", gqlErr.Error())
+	case e.Body != "":
+		fmt.Fprintf(&b, ": %s", e.Body)
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the underlying decoding or GraphQL error, if any.
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// snippet collapses whitespace and truncates body to maxSnippetBytes.
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) > maxSnippetBytes {
+		s = s[:maxSnippetBytes] + "..."
+	}
+	return s
+}
+
+// httpStatus returns the status code of an HTTPError in err's chain, or 0.
+func httpStatus(err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
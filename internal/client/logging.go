@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logSubsystem is the tflog subsystem used for API traffic. Its level follows
+// TF_LOG_PROVIDER_OCP and can be overridden with TF_LOG_PROVIDER_OCP_API.
+const logSubsystem = "api"
+
+// redacted replaces sensitive values in log output.
+const redacted = "***"
+
+// sensitiveVariables lists GraphQL variable names (lower-cased) whose values are never logged.
+var sensitiveVariables = map[string]struct{}{
+	"ignitionconfigdata": {},
+	"password":           {},
+	"token":              {},
+	"secret":             {},
+}
+
+// operation describes the GraphQL operation being executed.
+type operation struct {
+	// kind is "query" or "mutation".
+	kind string
+	// name is the operation name, e.g. "CreateVm", or "anonymous".
+	name string
+}
+
+func (op operation) mutation() bool { return op.kind == "mutation" }
+
+var operationPattern = regexp.MustCompile(`^(query|mutation|subscription)\b\s*([_A-Za-z][_0-9A-Za-z]*)?`)
+
+// parseOperation extracts the operation type and name from a GraphQL document.
+func parseOperation(query string) operation {
+	op := operation{kind: "query", name: "anonymous"}
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := operationPattern.FindStringSubmatch(line); m != nil {
+			op.kind = m[1]
+			if m[2] != "" {
+				op.name = m[2]
+			}
+		}
+		break
+	}
+	return op
+}
+
+// withLogging returns ctx with the API logging subsystem configured.
+func (c *Client) withLogging(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_OCP", strings.ToUpper(logSubsystem)))
+	if c.token != "" {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, logSubsystem, c.token)
+	}
+	return ctx
+}
+
+// redactVariables returns a deep copy of v with sensitive values replaced.
+func redactVariables(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if _, ok := sensitiveVariables[strings.ToLower(k)]; ok {
+				out[k] = redacted
+				continue
+			}
+			out[k] = redactVariables(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redactVariables(item)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redactVariables(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestClientLogsRedactedTraffic(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER_OCP_API", "TRACE")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	client := New(server.URL, "super-secret-token", true)
+	vars := map[string]interface{}{
+		"input": map[string]interface{}{
+			"hostname":           "immutable-vm",
+			"ignitionConfigData": "c2VjcmV0LWlnbml0aW9u",
+		},
+	}
+	if err := client.DoContext(ctx, "mutation CreateVmImmutable($input: X!) { ping }", vars, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs := output.String()
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("decode log output: %v", err)
+	}
+
+	var sawResponse bool
+	for _, entry := range entries {
+		if entry["@message"] == "received GraphQL response" {
+			sawResponse = true
+			if entry["operation"] != "CreateVmImmutable" {
+				t.Fatalf("expected operation CreateVmImmutable, got %v", entry["operation"])
+			}
+			if entry["status"] != float64(http.StatusOK) {
+				t.Fatalf("expected status 200, got %v", entry["status"])
+			}
+			if _, ok := entry["duration_ms"]; !ok {
+				t.Fatalf("expected duration_ms field")
+			}
+		}
+	}
+	if !sawResponse {
+		t.Fatalf("expected response log entry, got %s", logs)
+	}
+
+	if !strings.Contains(logs, "immutable-vm") {
+		t.Fatalf("expected non-sensitive variables to be logged")
+	}
+	for _, secret := range []string{"super-secret-token", "c2VjcmV0LWlnbml0aW9u"} {
+		if strings.Contains(logs, secret) {
+			t.Fatalf("log output leaks %q", secret)
+		}
+	}
+}
+
+func TestParseOperation(t *testing.T) {
+	for query, want := range map[string]operation{
+		"\nmutation CreateVm($input: X!) {}": {kind: "mutation", name: "CreateVm"},
+		"query GetVm($id: GlobalID!) {}":     {kind: "query", name: "GetVm"},
+		"query { ping }":                     {kind: "query", name: "anonymous"},
+		"{ ping }":                           {kind: "query", name: "anonymous"},
+	} {
+		if got := parseOperation(query); got != want {
+			t.Fatalf("parseOperation(%q) = %+v, want %+v", query, got, want)
+		}
+	}
+}
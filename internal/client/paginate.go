@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Pagination defaults used by Paginate.
+const (
+	DefaultPageSize = 100
+	DefaultMaxPages = 100
+)
+
+// PageInfo is the Relay pageInfo object of a connection.
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// Connection is a Relay cursor connection with nodes of type T.
+type Connection[T any] struct {
+	Edges []struct {
+		Node T `json:"node"`
+	} `json:"edges"`
+	PageInfo PageInfo `json:"pageInfo"`
+}
+
+// Paginate executes a Relay connection query page by page and returns the nodes of every page.
+//
+// The query must declare `$first: Int` and `$after: String`, pass them to the connection
+// named field, and select `pageInfo { hasNextPage endCursor }`. Paginate sets both
+// variables itself; any other variables are passed through unchanged.
+//
+// To guard against runaway loops, Paginate fails after DefaultMaxPages pages or when the
+// server returns the same cursor twice.
+func Paginate[T any](ctx context.Context, c *Client, query string, variables map[string]interface{}, field string) ([]T, error) {
+	vars := make(map[string]interface{}, len(variables)+2)
+	for k, v := range variables {
+		vars[k] = v
+	}
+	vars["first"] = DefaultPageSize
+
+	var nodes []T
+	var cursor string
+	for page := 0; page < DefaultMaxPages; page++ {
+		if cursor != "" {
+			vars["after"] = cursor
+		}
+
+		var resp map[string]json.RawMessage
+		if err := c.DoContext(ctx, query, vars, &resp); err != nil {
+			return nil, err
+		}
+
+		var conn Connection[T]
+		if raw, ok := resp[field]; ok {
+			if err := json.Unmarshal(raw, &conn); err != nil {
+				return nil, fmt.Errorf("decode %s: %w", field, err)
+			}
+		}
+
+		for _, edge := range conn.Edges {
+			nodes = append(nodes, edge.Node)
+		}
+
+		if !conn.PageInfo.HasNextPage {
+			return nodes, nil
+		}
+		if conn.PageInfo.EndCursor == "" || conn.PageInfo.EndCursor == cursor {
+			return nil, fmt.Errorf("paginate %s: server reported more pages without advancing the cursor", field)
+		}
+		cursor = conn.PageInfo.EndCursor
+	}
+
+	return nil, fmt.Errorf("paginate %s: more than %d pages of %d items, refine the filters", field, DefaultMaxPages, DefaultPageSize)
+}
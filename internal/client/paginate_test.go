@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	pages := map[string]map[string]interface{}{
+		"": {
+			"edges": []interface{}{
+				map[string]interface{}{"node": map[string]interface{}{"id": "c-1"}},
+			},
+			"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "cursor-1"},
+		},
+		"cursor-1": {
+			"edges": []interface{}{
+				map[string]interface{}{"node": map[string]interface{}{"id": "c-2"}},
+			},
+			"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": "cursor-2"},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if body.Variables["first"] != float64(DefaultPageSize) {
+			t.Fatalf("expected first %d, got %v", DefaultPageSize, body.Variables["first"])
+		}
+		if body.Variables["name"] != "customer-a" {
+			t.Fatalf("expected caller variables to be passed through, got %v", body.Variables)
+		}
+		after, _ := body.Variables["after"].(string)
+
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"customerList": pages[after],
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true)
+
+	type node struct {
+		ID string `json:"id"`
+	}
+	nodes, err := Paginate[node](context.Background(), client, "query { customerList }", map[string]interface{}{"name": "customer-a"}, "customerList")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].ID != "c-1" || nodes[1].ID != "c-2" {
+		t.Fatalf("expected nodes c-1 and c-2, got %+v", nodes)
+	}
+}
+
+func TestPaginateStuckCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"customerList": map[string]interface{}{
+					"edges":    []interface{}{},
+					"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "same"},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "token", true)
+
+	_, err := Paginate[struct{}](context.Background(), client, "query { customerList }", nil, "customerList")
+	if err == nil || !strings.Contains(err.Error(), "without advancing the cursor") {
+		t.Fatalf("expected stuck cursor error, got %v", err)
+	}
+}
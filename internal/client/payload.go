@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Typenames of the error branches shared by mutation union payloads.
+const (
+	TypenameValidationErrors     = "ValidationErrors"
+	TypenameUnauthorized         = "Unauthorized"
+	TypenameOperationUnavailable = "OperationUnavailable"
+
+	// TypenameTaskExecutionNode is the success branch of mutations that start an asynchronous job.
+	TypenameTaskExecutionNode = "TaskExecutionNode"
+)
+
+// TaskExecution is the success value of mutations returning a TaskExecutionNode.
+type TaskExecution struct {
+	ID string `json:"id"`
+}
+
+// FieldError is a single entry of a ValidationErrors payload.
+type FieldError struct {
+	Field    string   `json:"field"`
+	Messages []string `json:"messages"`
+}
+
+// PayloadError is returned when a mutation union payload resolves to an error branch
+// (ValidationErrors, Unauthorized, OperationUnavailable) or to an unexpected type.
+type PayloadError struct {
+	// Operation is the mutation field, e.g. "virtualHostCreate".
+	Operation string
+	// Typename is the __typename of the returned payload.
+	Typename string
+	Message  string
+	Errors   []FieldError
+	Reasons  []string
+}
+
+// Error implements the error interface.
+func (e *PayloadError) Error() string {
+	switch e.Typename {
+	case TypenameValidationErrors:
+		msg := e.Message
+		if len(e.Errors) > 0 {
+			details := make([]string, 0, len(e.Errors))
+			for _, fe := range e.Errors {
+				details = append(details, fmt.Sprintf("%s: %v", fe.Field, fe.Messages))
+			}
+			msg = strings.TrimSpace(fmt.Sprintf("%s (%s)", msg, strings.Join(details, "; ")))
+		}
+		if msg == "" {
+			msg = "validation failed without message"
+		}
+		return fmt.Sprintf("%s: %s", e.Operation, msg)
+
+	case TypenameUnauthorized, TypenameOperationUnavailable:
+		msg := e.Message
+		if msg == "" {
+			msg = e.Typename
+		}
+		if len(e.Reasons) > 0 {
+			msg = fmt.Sprintf("%s (reasons=%v)", msg, e.Reasons)
+		}
+		return fmt.Sprintf("%s: %s", e.Operation, msg)
+
+	default:
+		return fmt.Sprintf("%s: unexpected payload type %q", e.Operation, e.Typename)
+	}
+}
+
+// payloadEnvelope holds the fields shared by every branch of a union payload.
+type payloadEnvelope struct {
+	Typename string       `json:"__typename"`
+	Message  string       `json:"message,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Reasons  []string     `json:"reasons,omitempty"`
+}
+
+// DecodePayload decodes a mutation union payload.
+//
+// When the payload's __typename is one of successTypes, the payload is unmarshaled
+// into T and returned. Any other branch yields a *PayloadError.
+func DecodePayload[T any](operation string, raw json.RawMessage, successTypes ...string) (T, error) {
+	var result T
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return result, fmt.Errorf("%s: empty payload", operation)
+	}
+
+	var env payloadEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return result, fmt.Errorf("%s: decode payload: %w", operation, err)
+	}
+
+	for _, t := range successTypes {
+		if env.Typename == t {
+			if err := json.Unmarshal(raw, &result); err != nil {
+				return result, fmt.Errorf("%s: decode %s: %w", operation, t, err)
+			}
+			return result, nil
+		}
+	}
+
+	return result, &PayloadError{
+		Operation: operation,
+		Typename:  env.Typename,
+		Message:   env.Message,
+		Errors:    env.Errors,
+		Reasons:   env.Reasons,
+	}
+}
+
+// Mutate executes a mutation and decodes the union payload found under field.
+// See DecodePayload.
+func Mutate[T any](ctx context.Context, c *Client, query string, variables map[string]interface{}, field string, successTypes ...string) (T, error) {
+	var resp map[string]json.RawMessage
+	if err := c.DoContext(ctx, query, variables, &resp); err != nil {
+		var zero T
+		return zero, err
+	}
+	return DecodePayload[T](field, resp[field], successTypes...)
+}
+
+// IsValidationError reports whether err is a ValidationErrors payload.
+func IsValidationError(err error) bool {
+	var pErr *PayloadError
+	return errors.As(err, &pErr) && pErr.Typename == TypenameValidationErrors
+}
+
+// IsPermissionDenied reports whether err is an Unauthorized payload, i.e. the token is valid
+// but lacks permission for the operation or object.
+func IsPermissionDenied(err error) bool {
+	var pErr *PayloadError
+	return errors.As(err, &pErr) && pErr.Typename == TypenameUnauthorized
+}
+
+// IsOperationUnavailable reports whether err is an OperationUnavailable payload.
+func IsOperationUnavailable(err error) bool {
+	var pErr *PayloadError
+	return errors.As(err, &pErr) && pErr.Typename == TypenameOperationUnavailable
+}
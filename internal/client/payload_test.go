@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodePayloadSuccess(t *testing.T) {
+	raw := json.RawMessage(`{"__typename":"TaskExecutionNode","id":"task-1"}`)
+
+	task, err := DecodePayload[TaskExecution]("virtualHostResize", raw, TypenameTaskExecutionNode)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.ID != "task-1" {
+		t.Fatalf("expected task-1, got %q", task.ID)
+	}
+}
+
+func TestDecodePayloadErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		typename string
+		message  string
+	}{
+		{
+			name:     "validation",
+			raw:      `{"__typename":"ValidationErrors","message":"invalid input","errors":[{"field":"cpuCount","messages":["too large"]}]}`,
+			typename: TypenameValidationErrors,
+			message:  "virtualHostResize: invalid input (cpuCount: [too large])",
+		},
+		{
+			name:     "validation without message",
+			raw:      `{"__typename":"ValidationErrors"}`,
+			typename: TypenameValidationErrors,
+			message:  "virtualHostResize: validation failed without message",
+		},
+		{
+			name:     "unauthorized",
+			raw:      `{"__typename":"Unauthorized"}`,
+			typename: TypenameUnauthorized,
+			message:  "virtualHostResize: Unauthorized",
+		},
+		{
+			name:     "operation unavailable",
+			raw:      `{"__typename":"OperationUnavailable","message":"busy","reasons":["task running"]}`,
+			typename: TypenameOperationUnavailable,
+			message:  "virtualHostResize: busy (reasons=[task running])",
+		},
+		{
+			name:     "unexpected",
+			raw:      `{"__typename":"SomethingElse"}`,
+			typename: "SomethingElse",
+			message:  `virtualHostResize: unexpected payload type "SomethingElse"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecodePayload[TaskExecution]("virtualHostResize", json.RawMessage(tc.raw), TypenameTaskExecutionNode)
+
+			var pErr *PayloadError
+			if !errors.As(err, &pErr) {
+				t.Fatalf("expected PayloadError, got %T: %v", err, err)
+			}
+			if pErr.Typename != tc.typename {
+				t.Fatalf("expected typename %q, got %q", tc.typename, pErr.Typename)
+			}
+			if err.Error() != tc.message {
+				t.Fatalf("expected message %q, got %q", tc.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestDecodePayloadEmpty(t *testing.T) {
+	_, err := DecodePayload[TaskExecution]("virtualHostResize", json.RawMessage("null"), TypenameTaskExecutionNode)
+	if err == nil || !strings.Contains(err.Error(), "empty payload") {
+		t.Fatalf("expected empty payload error, got %v", err)
+	}
+}
+
+func TestMutate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"virtualHostUpdateTier":{"__typename":"Unauthorized","message":"denied"}}}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL, "token", true)
+	_, err := Mutate[TaskExecution](context.Background(), c, "mutation UpdateVmTier { virtualHostUpdateTier { __typename } }", nil, "virtualHostUpdateTier", TypenameTaskExecutionNode)
+	if !IsPermissionDenied(err) {
+		t.Fatalf("expected permission denied error, got %v", err)
+	}
+	if IsUnauthorized(err) {
+		t.Fatalf("expected an Unauthorized payload not to be reported as a credential error")
+	}
+	if err.Error() != "virtualHostUpdateTier: denied" {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
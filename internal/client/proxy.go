@@ -0,0 +1,120 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyOptions describes an explicit HTTP proxy configuration.
+type ProxyOptions struct {
+	// URL of the proxy, e.g. "http://proxy.example.com:3128".
+	URL string
+	// NoProxy is a comma-separated list of hosts, domain suffixes, IPs or CIDRs
+	// that are reached directly. "*" bypasses the proxy for every host.
+	NoProxy string
+	// Username and Password authenticate against the proxy. They override
+	// credentials embedded in URL.
+	Username string
+	Password string
+}
+
+// NewProxyFunc returns a Transport.Proxy function for opts.
+// When opts.URL is empty, the standard HTTPS_PROXY/HTTP_PROXY/NO_PROXY
+// environment variables are used.
+func NewProxyFunc(opts ProxyOptions) (func(*http.Request) (*url.URL, error), error) {
+	if opts.URL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme and host are required", opts.URL)
+	}
+	if opts.Username != "" {
+		proxyURL.User = url.UserPassword(opts.Username, opts.Password)
+	}
+
+	bypass := parseNoProxy(opts.NoProxy)
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass.matches(req.URL.Hostname()) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// WithProxyFunc sets the proxy selection function of the client transport.
+func WithProxyFunc(fn func(*http.Request) (*url.URL, error)) Option {
+	return func(c *Client) {
+		c.transport.Proxy = fn
+	}
+}
+
+// noProxy is a parsed NO_PROXY list.
+type noProxy struct {
+	all      bool
+	hosts    []string
+	suffixes []string
+	nets     []*net.IPNet
+}
+
+func parseNoProxy(value string) noProxy {
+	var np noProxy
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			np.all = true
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			np.nets = append(np.nets, ipNet)
+			continue
+		}
+		if host, _, err := net.SplitHostPort(entry); err == nil {
+			entry = host
+		}
+		if strings.HasPrefix(entry, ".") {
+			np.suffixes = append(np.suffixes, entry)
+			continue
+		}
+		// A bare domain matches the domain itself and all its subdomains.
+		np.hosts = append(np.hosts, entry)
+		np.suffixes = append(np.suffixes, "."+entry)
+	}
+	return np
+}
+
+func (np noProxy) matches(host string) bool {
+	if np.all {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, h := range np.hosts {
+		if host == h {
+			return true
+		}
+	}
+	for _, s := range np.suffixes {
+		if strings.HasSuffix(host, s) {
+			return true
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		for _, n := range np.nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientProxy(t *testing.T) {
+	var proxied bool
+	var proxyAuth string
+	// Stand-in forward proxy: answers proxied requests itself.
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		if r.URL.Host != "ocp.example.test" {
+			t.Fatalf("expected absolute request for ocp.example.test, got %q", r.URL.String())
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer proxy.Close()
+
+	proxyFunc, err := NewProxyFunc(ProxyOptions{
+		URL:      proxy.URL,
+		NoProxy:  "internal.example.test",
+		Username: "user",
+		Password: "secret",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := New("http://ocp.example.test/v2/graphql/", "token", true, WithProxyFunc(proxyFunc))
+	if err := client.DoContext(context.Background(), "query { ping }", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !proxied {
+		t.Fatalf("expected request to go through the proxy")
+	}
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	if proxyAuth != want {
+		t.Fatalf("expected Proxy-Authorization %q, got %q", want, proxyAuth)
+	}
+}
+
+func TestClientNoProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("request must bypass the proxy")
+	}))
+	defer proxy.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	proxyFunc, err := NewProxyFunc(ProxyOptions{URL: proxy.URL, NoProxy: "10.0.0.0/8, 127.0.0.1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := New(server.URL, "token", true, WithProxyFunc(proxyFunc))
+	if err := client.DoContext(context.Background(), "query { ping }", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNoProxyMatches(t *testing.T) {
+	np := parseNoProxy("example.com, .corp.test, 10.0.0.0/8")
+
+	for host, want := range map[string]bool{
+		"example.com":     true,
+		"api.example.com": true,
+		"notexample.com":  false,
+		"a.corp.test":     true,
+		"corp.test":       false,
+		"10.1.2.3":        true,
+		"192.0.2.1":       false,
+	} {
+		if got := np.matches(host); got != want {
+			t.Fatalf("matches(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
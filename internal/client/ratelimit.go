@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Default client-side limits applied by the provider.
+const (
+	DefaultMaxRequestsPerSecond   = 10.0
+	DefaultMaxConcurrentMutations = 5
+)
+
+// WithRateLimit limits the client to requestsPerSecond HTTP requests, including
+// retries, with a burst of the same size. Zero or a negative value disables the limit.
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newTokenBucket(requestsPerSecond, int(math.Max(1, math.Ceil(requestsPerSecond))))
+	}
+}
+
+// WithMaxConcurrentMutations caps the number of mutations in flight at once.
+// Zero or a negative value disables the cap.
+func WithMaxConcurrentMutations(n int) Option {
+	return func(c *Client) {
+		if n <= 0 {
+			c.mutations = nil
+			return
+		}
+		c.mutations = make(chan struct{}, n)
+	}
+}
+
+// tokenBucket is a minimal token-bucket rate limiter safe for concurrent use.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+// It returns how long the caller was delayed.
+func (b *tokenBucket) wait(ctx context.Context) (time.Duration, error) {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	// Reserve a token; a negative balance means the caller has to wait for it.
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay == 0 {
+		return 0, nil
+	}
+	if err := sleep(ctx, delay); err != nil {
+		// Give the reservation back so cancelled callers don't delay others.
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return 0, err
+	}
+	return delay, nil
+}
+
+// acquireMutationSlot blocks until a mutation slot is free or ctx is done.
+// The returned function releases the slot.
+func (c *Client) acquireMutationSlot(ctx context.Context) (func(), error) {
+	if c.mutations == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.mutations <- struct{}{}:
+		return func() { <-c.mutations }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
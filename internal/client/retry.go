@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default retry settings used when none are configured.
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryMinWait = 1 * time.Second
+	DefaultRetryMaxWait = 30 * time.Second
+)
+
+// RetryPolicy controls how transient API failures are retried.
+//
+// Queries are retried on network errors, on 429/502/503/504 responses and on
+// GraphQL rate-limit errors. Certificate verification failures and host names
+// that don't resolve are never retried.
+// Mutations are retried only when the request was never written to the
+// connection, i.e. the server provably did not receive it.
+type RetryPolicy struct {
+	// MaxRetries is the number of additional attempts after the first one.
+	MaxRetries int
+	// MinWait is the base delay of the exponential backoff.
+	MinWait time.Duration
+	// MaxWait caps both the backoff delay and any Retry-After value.
+	MaxWait time.Duration
+}
+
+// WithRetryPolicy sets the retry policy used for transient failures.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// retryableError marks a failed attempt that may be retried.
+type retryableError struct {
+	err error
+	// after is the delay requested by the server via Retry-After, if any.
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// isPermanentTransportError reports whether a transport error will fail the same way on
+// every attempt, such as an untrusted server certificate or a host name that doesn't resolve.
+// Retrying those only delays the real error.
+func isPermanentTransportError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &certErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && !dnsErr.IsTemporary && !dnsErr.IsTimeout
+}
+
+// backoff returns the delay before the given retry attempt (0-based).
+// A server-provided Retry-After takes precedence over the computed backoff.
+// Both are capped by MaxWait.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	maxWait := p.MaxWait
+	if maxWait < p.MinWait {
+		maxWait = p.MinWait
+	}
+
+	if retryAfter > 0 {
+		return min(retryAfter, maxWait)
+	}
+
+	wait := p.MinWait
+	for i := 0; i < attempt && wait < maxWait; i++ {
+		wait *= 2
+	}
+	wait = min(wait, maxWait)
+
+	// Equal jitter: keep half of the delay and randomize the rest so that
+	// parallel resources don't retry in lockstep.
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryableStatus reports whether an HTTP status indicates a transient failure.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds or as an HTTP date.
+// It returns 0 when the header is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
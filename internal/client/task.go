@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultPollInterval is the delay between two status queries in Poll and WaitForTask.
+const DefaultPollInterval = 10 * time.Second
+
+const queryGetTaskExecution = `
+query GetTaskExecution($id: GlobalID!) {
+  taskExecution(id: $id) {
+    id
+    state
+    message
+  }
+}
+`
+
+// Task is the status of an asynchronous TaskExecutionNode.
+type Task struct {
+	ID      string `json:"id"`
+	State   string `json:"state"`
+	Message string `json:"message"`
+}
+
+// Values of the TaskExecution state enum.
+const (
+	TaskStatePending         = "PENDING"
+	TaskStateRunning         = "RUNNING"
+	TaskStateSuccess         = "SUCCESS"
+	TaskStatePartiallyFailed = "PARTIALLY_FAILED"
+	TaskStateFailed          = "FAILED"
+	TaskStateCancelled       = "CANCELLED"
+)
+
+// Succeeded reports whether the task finished successfully.
+func (t *Task) Succeeded() bool {
+	return t.State == TaskStateSuccess
+}
+
+// Failed reports whether the task reached a terminal failure state.
+func (t *Task) Failed() bool {
+	switch t.State {
+	case TaskStatePartiallyFailed, TaskStateFailed, TaskStateCancelled:
+		return true
+	}
+	return false
+}
+
+// known reports whether the state is part of the TaskExecution state enum.
+func (t *Task) known() bool {
+	return t.State == TaskStatePending || t.State == TaskStateRunning || t.Succeeded() || t.Failed()
+}
+
+// TaskError is returned by WaitForTask when the task ends in a failure state.
+type TaskError struct {
+	TaskID  string
+	State   string
+	Message string
+}
+
+// Error implements the error interface.
+func (e *TaskError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = "no failure message reported"
+	}
+	return fmt.Sprintf("task %s ended in state %s: %s", e.TaskID, e.State, msg)
+}
+
+// IsTaskFailed reports whether err is a *TaskError.
+func IsTaskFailed(err error) bool {
+	var tErr *TaskError
+	return errors.As(err, &tErr)
+}
+
+// WithPollInterval sets how often Poll and WaitForTask query the API.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		if interval > 0 {
+			c.pollInterval = interval
+		}
+	}
+}
+
+// Poll calls check every poll interval until it reports done, returns an error,
+// or ctx is done. When ctx ends first, the context error is returned.
+func (c *Client) Poll(ctx context.Context, check func(context.Context) (bool, error)) error {
+	for {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+		if err := sleep(ctx, c.pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// GetTask returns the current status of a task.
+func (c *Client) GetTask(ctx context.Context, id string) (*Task, error) {
+	var resp struct {
+		TaskExecution *Task `json:"taskExecution"`
+	}
+	if err := c.DoContext(ctx, queryGetTaskExecution, map[string]interface{}{"id": id}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.TaskExecution == nil {
+		return nil, fmt.Errorf("task %s not found", id)
+	}
+	return resp.TaskExecution, nil
+}
+
+// WaitForTask polls the task until it reaches a terminal state or ctx is done.
+// A failed task is returned as *TaskError; the deadline of ctx bounds the wait.
+func (c *Client) WaitForTask(ctx context.Context, id string) (*Task, error) {
+	ctx = c.withLogging(ctx)
+
+	var task *Task
+	lastState := "unknown"
+	err := c.Poll(ctx, func(ctx context.Context) (bool, error) {
+		t, err := c.GetTask(ctx, id)
+		if err != nil {
+			return false, err
+		}
+
+		if t.State != lastState {
+			tflog.SubsystemDebug(ctx, logSubsystem, "task state changed", map[string]interface{}{
+				"task":  id,
+				"state": t.State,
+			})
+			lastState = t.State
+			if !t.known() {
+				tflog.SubsystemWarn(ctx, logSubsystem, "task reported an unrecognised state, waiting until it changes", map[string]interface{}{
+					"task":  id,
+					"state": t.State,
+				})
+			}
+		}
+
+		task = t
+		return t.Succeeded() || t.Failed(), nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, c.waitError(ctx, id, lastState)
+		}
+		return nil, fmt.Errorf("failed to get status of task %s: %w", id, err)
+	}
+
+	if task.Failed() {
+		return task, &TaskError{TaskID: id, State: task.State, Message: task.Message}
+	}
+	return task, nil
+}
+
+// waitError describes why WaitForTask stopped before the task finished.
+func (c *Client) waitError(ctx context.Context, id, state string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out waiting for task %s (last state %s): %w", id, state, context.DeadlineExceeded)
+	}
+	return fmt.Errorf("cancelled while waiting for task %s (last state %s): %w", id, state, context.Canceled)
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func taskServer(t *testing.T, states ...string) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&polls, 1))
+		state := states[len(states)-1]
+		if n <= len(states) {
+			state = states[n-1]
+		}
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"taskExecution": map[string]interface{}{
+					"id":      "task-1",
+					"state":   state,
+					"message": "disk quota exceeded",
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &polls
+}
+
+func TestWaitForTaskSucceeded(t *testing.T) {
+	server, polls := taskServer(t, "PENDING", "RUNNING", "SUCCESS")
+
+	c := New(server.URL, "token", true, WithPollInterval(time.Millisecond))
+	task, err := c.WaitForTask(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !task.Succeeded() {
+		t.Fatalf("expected succeeded task, got state %q", task.State)
+	}
+	if got := atomic.LoadInt32(polls); got != 3 {
+		t.Fatalf("expected 3 polls, got %d", got)
+	}
+}
+
+func TestWaitForTaskFailed(t *testing.T) {
+	for _, state := range []string{TaskStateFailed, TaskStatePartiallyFailed, TaskStateCancelled} {
+		t.Run(state, func(t *testing.T) {
+			server, _ := taskServer(t, "RUNNING", state)
+
+			c := New(server.URL, "token", true, WithPollInterval(time.Millisecond))
+			_, err := c.WaitForTask(context.Background(), "task-1")
+
+			var tErr *TaskError
+			if !errors.As(err, &tErr) {
+				t.Fatalf("expected TaskError, got %T: %v", err, err)
+			}
+			if tErr.State != state || tErr.Message != "disk quota exceeded" {
+				t.Fatalf("unexpected task error: %+v", tErr)
+			}
+		})
+	}
+}
+
+func TestWaitForTaskUnknownStateKeepsPolling(t *testing.T) {
+	server, polls := taskServer(t, "RUNNING", "VERIFYING", "SUCCESS")
+
+	c := New(server.URL, "token", true, WithPollInterval(time.Millisecond))
+	if _, err := c.WaitForTask(context.Background(), "task-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(polls); got != 3 {
+		t.Fatalf("expected 3 polls, got %d", got)
+	}
+}
+
+func TestWaitForTaskTimeout(t *testing.T) {
+	server, _ := taskServer(t, "RUNNING")
+
+	c := New(server.URL, "token", true, WithPollInterval(5*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.WaitForTask(ctx, "task-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out waiting for task task-1 (last state RUNNING)") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
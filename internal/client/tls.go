@@ -0,0 +1,60 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// TLSOptions describes the TLS settings used to reach the API.
+type TLSOptions struct {
+	// Insecure disables server certificate verification.
+	Insecure bool
+	// CACertPEM holds additional PEM-encoded CA certificates trusted on top of the system pool.
+	CACertPEM []byte
+	// ClientCertPEM and ClientKeyPEM hold a PEM-encoded client certificate and key for mTLS.
+	ClientCertPEM []byte
+	ClientKeyPEM  []byte
+}
+
+// NewTLSConfig builds a tls.Config from opts.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.Insecure, // When true, skip TLS certificate verification.
+	}
+
+	if len(opts.CACertPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(opts.CACertPEM) {
+			return nil, errors.New("CA bundle contains no valid PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	hasCert, hasKey := len(opts.ClientCertPEM) > 0, len(opts.ClientKeyPEM) > 0
+	if hasCert != hasKey {
+		return nil, errors.New("client certificate and client key must be provided together")
+	}
+	if hasCert {
+		cert, err := tls.X509KeyPair(opts.ClientCertPEM, opts.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// WithTLSConfig replaces the TLS configuration of the client transport.
+// It takes precedence over the insecure flag passed to New.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.transport.TLSClientConfig = cfg
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	// Without the CA the server certificate must be rejected.
+	untrusted := New(server.URL, "token", false, WithRetryPolicy(RetryPolicy{}))
+	if err := untrusted.DoContext(context.Background(), "query { ping }", nil, nil); err == nil {
+		t.Fatalf("expected certificate verification error, got none")
+	}
+
+	tlsConfig, err := NewTLSConfig(TLSOptions{CACertPEM: caPEM})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trusted := New(server.URL, "token", false, WithTLSConfig(tlsConfig))
+	if err := trusted.DoContext(context.Background(), "query { ping }", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	if _, err := NewTLSConfig(TLSOptions{CACertPEM: []byte("not a certificate")}); err == nil {
+		t.Fatalf("expected error for invalid CA bundle")
+	}
+	if _, err := NewTLSConfig(TLSOptions{ClientCertPEM: []byte("cert")}); err == nil {
+		t.Fatalf("expected error for client certificate without key")
+	}
+}
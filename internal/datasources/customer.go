@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceCustomer returns a data source that looks up a customer by name.
@@ -30,14 +31,18 @@ func DataSourceCustomer() *schema.Resource {
 }
 
 const queryCustomerByName = `
-query CustomerByName($name: StrFilterLookup) {
-  customerList(filters: { name: $name }) {
+query CustomerByName($name: StrFilterLookup, $first: Int, $after: String) {
+  customerList(filters: { name: $name }, first: $first, after: $after) {
     edges {
       node {
         id
         name
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -53,29 +58,24 @@ func dataSourceCustomerRead(ctx context.Context, d *schema.ResourceData, meta in
 		},
 	}
 
-	var resp struct {
-		CustomerList struct {
-			Edges []struct {
-				Node struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"customerList"`
+	type customerNode struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
 	}
 
-	if err := client.Do(queryCustomerByName, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[customerNode](ctx, client, queryCustomerByName, vars, "customerList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	if len(resp.CustomerList.Edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf("no customer found with name %q", name)
 	}
-	if len(resp.CustomerList.Edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf("multiple customers found for name %q, please refine", name)
 	}
 
-	id := resp.CustomerList.Edges[0].Node.ID
+	id := nodes[0].ID
 	d.SetId(id)
 	d.Set("id", id)
 
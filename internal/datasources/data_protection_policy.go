@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceDataProtectionPolicy returns a data source that looks up a data protection policy by name.
@@ -46,8 +47,8 @@ func DataSourceDataProtectionPolicy() *schema.Resource {
 }
 
 const queryDataProtectionPolicyByFilters = `
-query DataProtectionPolicyByFilters($filters: DataProtectionPolicyFilter) {
-  dataProtectionPolicyList(filters: $filters, first: 100) {
+query DataProtectionPolicyByFilters($filters: DataProtectionPolicyFilter, $first: Int, $after: String) {
+  dataProtectionPolicyList(filters: $filters, first: $first, after: $after) {
     edges {
       node {
         id
@@ -58,6 +59,10 @@ query DataProtectionPolicyByFilters($filters: DataProtectionPolicyFilter) {
         }
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -113,42 +118,35 @@ func dataProtectionPolicyRead(ctx context.Context, d *schema.ResourceData, meta
 		"filters": filters,
 	}
 
-	var resp struct {
-		DataProtectionPolicyList struct {
-			Edges []struct {
-				Node struct {
-					ID       string `json:"id"`
-					Note     string `json:"note"`
-					Customer struct {
-						ID   string `json:"id"`
-						Name string `json:"name"`
-					} `json:"customer"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"dataProtectionPolicyList"`
+	type dataProtectionPolicyNode struct {
+		ID       string `json:"id"`
+		Note     string `json:"note"`
+		Customer struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"customer"`
 	}
 
-	if err := client.Do(queryDataProtectionPolicyByFilters, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[dataProtectionPolicyNode](ctx, client, queryDataProtectionPolicyByFilters, vars, "dataProtectionPolicyList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	edges := resp.DataProtectionPolicyList.Edges
-
-	if len(edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf(
 			"no data protection policy found for customer_id=%q, project_id=%q, solution_type=%q, note=%q",
 			customerID, projectID, solutionType, note,
 		)
 	}
 
-	if len(edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf(
 			"multiple data protection policies found for customer_id=%q, project_id=%q, solution_type=%q, note=%q (after DISTINCT); must be unique",
 			customerID, projectID, solutionType, note,
 		)
 	}
 
-	node := edges[0].Node
+	node := nodes[0]
 
 	d.SetId(node.ID)
 	_ = d.Set("id", node.ID)
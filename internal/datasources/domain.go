@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceDomain returns a data source that looks up a domain by name.
@@ -35,14 +36,18 @@ func DataSourceDomain() *schema.Resource {
 }
 
 const queryDomainByFilters = `
-query DomainByFilters($filters: DomainFilter) {
-  domainList(filters: $filters, first: 100) {
+query DomainByFilters($filters: DomainFilter, $first: Int, $after: String) {
+  domainList(filters: $filters, first: $first, after: $after) {
     edges {
       node {
         id
         name
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -66,38 +71,31 @@ func dataSourceDomainRead(ctx context.Context, d *schema.ResourceData, meta inte
 		},
 	}
 
-	var resp struct {
-		DomainList struct {
-			Edges []struct {
-				Node struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"domainList"`
+	type domainNode struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
 	}
 
-	if err := client.Do(queryDomainByFilters, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[domainNode](ctx, client, queryDomainByFilters, vars, "domainList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	edges := resp.DomainList.Edges
-
-	if len(edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf(
 			"no domain found for customer %q with name %q",
 			customerID, name,
 		)
 	}
 
-	if len(edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf(
 			"multiple domains found for customer %q with name %q",
 			customerID, name,
 		)
 	}
 
-	node := edges[0].Node
+	node := nodes[0]
 
 	d.SetId(node.ID)
 	_ = d.Set("id", node.ID)
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceNetwork returns a data source that looks up a network by name within a customer.
@@ -35,8 +36,8 @@ func DataSourceNetwork() *schema.Resource {
 }
 
 const queryNetworkByName = `
-query NetworkByName($name: StrFilterLookup, $customer: CustomerFilter) {
-  networkList(filters: { name: $name, customer: $customer }) {
+query NetworkByName($name: StrFilterLookup, $customer: CustomerFilter, $first: Int, $after: String) {
+  networkList(filters: { name: $name, customer: $customer }, first: $first, after: $after) {
     edges {
       node {
         id
@@ -44,6 +45,10 @@ query NetworkByName($name: StrFilterLookup, $customer: CustomerFilter) {
         customer { id }
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -65,33 +70,27 @@ func dataSourceNetworkRead(ctx context.Context, d *schema.ResourceData, meta int
 		},
 	}
 
-	var resp struct {
-		NetworkList struct {
-			Edges []struct {
-				Node struct {
-					ID       string `json:"id"`
-					Name     string `json:"name"`
-					Customer struct {
-						ID string `json:"id"`
-					} `json:"customer"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"networkList"`
+	type networkNode struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Customer struct {
+			ID string `json:"id"`
+		} `json:"customer"`
 	}
 
-	if err := client.Do(queryNetworkByName, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[networkNode](ctx, client, queryNetworkByName, vars, "networkList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	edges := resp.NetworkList.Edges
-	if len(edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf("no network found with name %q for customer %q", name, customerID)
 	}
-	if len(edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf("multiple networks found with name %q for customer %q, please refine filters", name, customerID)
 	}
 
-	id := edges[0].Node.ID
+	id := nodes[0].ID
 
 	d.SetId(id)
 	_ = d.Set("id", id)
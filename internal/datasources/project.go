@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceProject returns a data source that looks up a project by name within a customer.
@@ -35,8 +36,8 @@ func DataSourceProject() *schema.Resource {
 }
 
 const queryProjectByNameAndCustomer = `
-query ProjectByNameAndCustomer($name: StrFilterLookup, $customer: CustomerFilter) {
-  projectList(filters: { name: $name, customer: $customer }) {
+query ProjectByNameAndCustomer($name: StrFilterLookup, $customer: CustomerFilter, $first: Int, $after: String) {
+  projectList(filters: { name: $name, customer: $customer }, first: $first, after: $after) {
     edges {
       node {
         id
@@ -47,6 +48,10 @@ query ProjectByNameAndCustomer($name: StrFilterLookup, $customer: CustomerFilter
         }
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -68,35 +73,28 @@ func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta int
 		},
 	}
 
-	var resp struct {
-		ProjectList struct {
-			Edges []struct {
-				Node struct {
-					ID       string `json:"id"`
-					Name     string `json:"name"`
-					Customer struct {
-						ID   string `json:"id"`
-						Name string `json:"name"`
-					} `json:"customer"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"projectList"`
+	type projectNode struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Customer struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"customer"`
 	}
 
-	if err := client.Do(queryProjectByNameAndCustomer, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[projectNode](ctx, client, queryProjectByNameAndCustomer, vars, "projectList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	edges := resp.ProjectList.Edges
-
-	if len(edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf("no project found with name %q for customer %q", name, customerID)
 	}
-	if len(edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf("multiple projects found with name %q for given customer, please refine", name)
 	}
 
-	id := edges[0].Node.ID
+	id := nodes[0].ID
 	d.SetId(id)
 	_ = d.Set("id", id)
 
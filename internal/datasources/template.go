@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceTemplate returns a data source that looks up a template by name.
@@ -46,14 +47,18 @@ func DataSourceTemplate() *schema.Resource {
 }
 
 const queryTemplateByName = `
-query TemplateByName($filters: TemplateFilter) {
-  templateList(filters: $filters) {
+query TemplateByName($filters: TemplateFilter, $first: Int, $after: String) {
+  templateList(filters: $filters, first: $first, after: $after) {
     edges {
       node {
         id
         name
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -99,38 +104,31 @@ func dataSourceTemplateRead(ctx context.Context, d *schema.ResourceData, meta in
 		"filters": filters,
 	}
 
-	var resp struct {
-		TemplateList struct {
-			Edges []struct {
-				Node struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"templateList"`
+	type templateNode struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
 	}
 
-	if err := client.Do(queryTemplateByName, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[templateNode](ctx, client, queryTemplateByName, vars, "templateList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	edges := resp.TemplateList.Edges
-
-	if len(edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf(
 			"no template found with name %q for customer %q in region %q (solution_type %q)",
 			name, customerID, region, solutionType,
 		)
 	}
 
-	if len(edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf(
 			"multiple templates found with name %q for customer %q in region %q (solution_type %q), must be unique",
 			name, customerID, region, solutionType,
 		)
 	}
 
-	id := edges[0].Node.ID
+	id := nodes[0].ID
 
 	d.SetId(id)
 	_ = d.Set("id", id)
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceTier returns a data source that looks up a tier by name.
@@ -36,14 +37,18 @@ func DataSourceTier() *schema.Resource {
 }
 
 const queryTierByName = `
-query TierByName($name: StrFilterLookup, $solutionType: SolutionTypeEnumFilterLookup) {
-  tierList(filters: { name: $name, solutionType: $solutionType }) {
+query TierByName($name: StrFilterLookup, $solutionType: SolutionTypeEnumFilterLookup, $first: Int, $after: String) {
+  tierList(filters: { name: $name, solutionType: $solutionType }, first: $first, after: $after) {
     edges {
       node {
         id
         name
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -71,35 +76,28 @@ func dataSourceTierRead(ctx context.Context, d *schema.ResourceData, meta interf
 		"solutionType": solutionTypeFilter,
 	}
 
-	var resp struct {
-		TierList struct {
-			Edges []struct {
-				Node struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"tierList"`
+	type tierNode struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
 	}
 
-	if err := client.Do(queryTierByName, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[tierNode](ctx, client, queryTierByName, vars, "tierList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	edges := resp.TierList.Edges
-
-	if len(edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf("no tier found with name %q for solution_type %q", name, solutionType)
 	}
 
-	if len(edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf(
 			"multiple tiers found with name %q for solution_type %q, must be unique",
 			name, solutionType,
 		)
 	}
 
-	id := edges[0].Node.ID
+	id := nodes[0].ID
 
 	d.SetId(id)
 	_ = d.Set("id", id)
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // DataSourceVcenter returns a data source that looks up a vCenter by name within a customer.
@@ -36,8 +37,8 @@ func DataSourceVcenter() *schema.Resource {
 }
 
 const queryVcenterByNameAndCustomer = `
-query VcenterByNameAndCustomer($name: StrFilterLookup, $customer: CustomerFilter) {
-  vcenterList(filters: { name: $name, customer: $customer, DISTINCT: true }) {
+query VcenterByNameAndCustomer($name: StrFilterLookup, $customer: CustomerFilter, $first: Int, $after: String) {
+  vcenterList(filters: { name: $name, customer: $customer, DISTINCT: true }, first: $first, after: $after) {
     edges {
       node {
         id
@@ -48,6 +49,10 @@ query VcenterByNameAndCustomer($name: StrFilterLookup, $customer: CustomerFilter
         }
       }
     }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
   }
 }
 `
@@ -58,19 +63,13 @@ func dataSourceVcenterRead(ctx context.Context, d *schema.ResourceData, meta int
 	customerID := d.Get("customer_id").(string)
 	name := d.Get("name").(string)
 
-	var resp struct {
-		VcenterList struct {
-			Edges []struct {
-				Node struct {
-					ID       string `json:"id"`
-					Name     string `json:"name"`
-					Customer struct {
-						ID   string `json:"id"`
-						Name string `json:"name"`
-					} `json:"customer"`
-				} `json:"node"`
-			} `json:"edges"`
-		} `json:"vcenterList"`
+	type vcenterNode struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Customer struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"customer"`
 	}
 
 	vars := map[string]interface{}{
@@ -84,20 +83,19 @@ func dataSourceVcenterRead(ctx context.Context, d *schema.ResourceData, meta int
 		},
 	}
 
-	if err := client.Do(queryVcenterByNameAndCustomer, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	nodes, err := ocpclient.Paginate[vcenterNode](ctx, client, queryVcenterByNameAndCustomer, vars, "vcenterList")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	edges := resp.VcenterList.Edges
-
-	if len(edges) == 0 {
+	if len(nodes) == 0 {
 		return diag.Errorf("no vcenter found with name %q for customer %q", name, customerID)
 	}
-	if len(edges) > 1 {
+	if len(nodes) > 1 {
 		return diag.Errorf("multiple vcenters found with name %q for given customer, please refine", name)
 	}
 
-	id := edges[0].Node.ID
+	id := nodes[0].ID
 	d.SetId(id)
 	_ = d.Set("id", id)
 
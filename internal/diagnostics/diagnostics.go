@@ -0,0 +1,89 @@
+// Package diagnostics translates technical errors returned by internal/client
+// into user-facing Terraform diagnostics.
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+// FromErr converts an API client error into diagnostics.
+//
+// HTTP-level failures are mapped to a short summary describing the likely cause
+// (authentication, maintenance, payload size, ...) with the raw error in the detail.
+// Any other error is reported as-is, like diag.FromErr.
+func FromErr(err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	summary, hint := describe(err)
+	if summary == "" {
+		return diag.FromErr(err)
+	}
+
+	detail := err.Error()
+	if hint != "" {
+		detail = fmt.Sprintf("%s\n\n%s", hint, detail)
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   detail,
+	}}
+}
+
+// describe returns a summary and a hint for known error classes, or empty strings.
+func describe(err error) (string, string) {
+	if ocpclient.IsUnauthorized(err) {
+		return "OCP API authentication failed",
+			"The API rejected the credentials. Check the provider `token` argument or the OCP_TOKEN environment variable, and that the token has not expired."
+	}
+	if ocpclient.IsPermissionDenied(err) {
+		return "OCP API permission denied",
+			"The token is valid but is not allowed to perform this operation. Check the roles granted to the token's user for the project or virtual host."
+	}
+	if ocpclient.IsRateLimited(err) {
+		return "OCP API rate limit exceeded",
+			"The API throttled the request. Retry later or reduce Terraform parallelism."
+	}
+
+	if ocpclient.IsTaskFailed(err) {
+		return "OCP task failed",
+			"The API accepted the request but the asynchronous task reported a failure. Check the task in the portal for details."
+	}
+	if ocpclient.IsOperationUnavailable(err) {
+		return "OCP API operation unavailable",
+			"The portal refused to run the operation right now, for example because another task is running on the virtual host. Retry later."
+	}
+
+	var httpErr *ocpclient.HTTPError
+	if !errors.As(err, &httpErr) {
+		return "", ""
+	}
+
+	switch code := httpErr.StatusCode; {
+	case code == http.StatusNotFound:
+		return "OCP API endpoint not found",
+			"The API endpoint returned 404. Check the provider `endpoint` argument."
+	case code == http.StatusRequestEntityTooLarge:
+		return "OCP API request too large",
+			"The request payload exceeds the server limit. Large attributes such as `ignition_config_data` are the usual cause."
+	case code == http.StatusBadGateway, code == http.StatusServiceUnavailable, code == http.StatusGatewayTimeout:
+		return "OCP API unavailable",
+			"The portal or a proxy in front of it is unavailable, possibly due to maintenance. Retry later."
+	case code >= 500:
+		return "OCP API server error", ""
+	case code >= 200 && code <= 299:
+		return "OCP API returned an unexpected response",
+			"The response is not a GraphQL JSON document. A proxy or SSO gateway may be intercepting requests; check the provider `endpoint` argument."
+	default:
+		return fmt.Sprintf("OCP API request failed with HTTP %d", code), ""
+	}
+}
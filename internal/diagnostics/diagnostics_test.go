@@ -0,0 +1,70 @@
+package diagnostics
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+func TestFromErr(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		summary string
+	}{
+		{
+			name:    "unauthorized",
+			err:     &ocpclient.HTTPError{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"},
+			summary: "OCP API authentication failed",
+		},
+		{
+			name:    "maintenance",
+			err:     &ocpclient.HTTPError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"},
+			summary: "OCP API unavailable",
+		},
+		{
+			name:    "payload too large",
+			err:     &ocpclient.HTTPError{StatusCode: http.StatusRequestEntityTooLarge, Status: "413 Request Entity Too Large"},
+			summary: "OCP API request too large",
+		},
+		{
+			name:    "unauthorized payload",
+			err:     &ocpclient.PayloadError{Operation: "virtualHostCreate", Typename: ocpclient.TypenameUnauthorized},
+			summary: "OCP API permission denied",
+		},
+		{
+			name:    "operation unavailable payload",
+			err:     &ocpclient.PayloadError{Operation: "virtualHostResize", Typename: ocpclient.TypenameOperationUnavailable, Message: "busy"},
+			summary: "OCP API operation unavailable",
+		},
+		{
+			name:    "validation payload",
+			err:     &ocpclient.PayloadError{Operation: "virtualHostCreate", Typename: ocpclient.TypenameValidationErrors, Message: "invalid"},
+			summary: "virtualHostCreate: invalid",
+		},
+		{
+			name:    "other",
+			err:     errors.New("boom"),
+			summary: "boom",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			diags := FromErr(tc.err)
+			if !diags.HasError() {
+				t.Fatalf("expected error diagnostic")
+			}
+			if diags[0].Summary != tc.summary {
+				t.Fatalf("expected summary %q, got %q", tc.summary, diags[0].Summary)
+			}
+			if tc.summary != tc.err.Error() && !strings.Contains(diags[0].Detail, tc.err.Error()) {
+				t.Fatalf("expected detail to contain %q, got %q", tc.err.Error(), diags[0].Detail)
+			}
+		})
+	}
+}
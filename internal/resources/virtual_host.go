@@ -2,14 +2,41 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
+// payloadErrorSelection selects the error branches shared by mutation union payloads.
+const payloadErrorSelection = `
+    ... on ValidationErrors {
+      message
+      errors {
+        field
+        messages
+      }
+    }
+    ... on Unauthorized {
+      message
+    }
+    ... on OperationUnavailable {
+      message
+      reasons
+    }
+`
+
+// defaultVirtualHostTaskTimeout bounds waiting for asynchronous virtual host tasks.
+const defaultVirtualHostTaskTimeout = 30 * time.Minute
+
 // ResourceVirtualHost defines the ocp_virtual_host resource schema and CRUD operations.
 func ResourceVirtualHost() *schema.Resource {
 	return &schema.Resource{
@@ -18,18 +45,37 @@ func ResourceVirtualHost() *schema.Resource {
 		UpdateContext: ResourceVirtualHostUpdate,
 		DeleteContext: ResourceVirtualHostDelete,
 
+		// Sizing, template, tier, disk and interface mistakes, resizes that need a restart and
+		// replacements of protected virtual hosts are reported at plan time.
+		CustomizeDiff: customdiff.All(
+			customizeVirtualHostDiff,
+			customizeVirtualHostDisksDiff,
+			customizeVirtualHostInterfacesDiff,
+			customizeVirtualHostResizeDiff,
+			customizeDeletionProtectionDiff(ResourceVirtualHost),
+		),
+
 		// Import supports: terraform import ocp_virtual_host.<name> <VirtualHost GlobalID>
-		// The ID must be the GraphQL GlobalID (VirtualHostNode.id) because Read() uses:
+		// as well as hostname=<name>[,project=<name>] and uuid=<uuid> lookups, which are
+		// resolved to the GlobalID (VirtualHostNode.id) that Read() uses:
 		//   virtualHost(id: GlobalID!)
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importVirtualHost,
+		},
+
+		// Provisioning (see wait_for_state), resize, tier changes and deletion are awaited within these timeouts.
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultVirtualHostTaskTimeout),
+			Update: schema.DefaultTimeout(defaultVirtualHostTaskTimeout),
+			Delete: schema.DefaultTimeout(defaultVirtualHostTaskTimeout),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:        schema.TypeString,
-				Description: "Region.",
+				Description: "Region. Changing this forces a new virtual host.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"customer_id": {
 				Type:        schema.TypeString,
@@ -45,17 +91,19 @@ func ResourceVirtualHost() *schema.Resource {
 			},
 			"hostname": {
 				Type:        schema.TypeString,
-				Description: "Hostname.",
+				Description: "Hostname. Changing this forces a new virtual host.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"domain_id": {
 				Type:        schema.TypeString,
-				Description: "ID of the domain.",
+				Description: "ID of the domain. Changing this forces a new virtual host.",
 				Required:    true,
+				ForceNew:    true,
 			},
 			"allow_resize_restart": {
 				Type:        schema.TypeBool,
-				Description: "Allow resize restart.",
+				Description: "Allow restarting the virtual host when a resize cannot be applied while it is running. When false, such resizes fail at plan time.",
 				Optional:    true,
 				Default:     true,
 			},
@@ -80,6 +128,8 @@ func ResourceVirtualHost() *schema.Resource {
 				Description: "ID of the storage tier assigned to the virtual host.",
 				Required:    true,
 			},
+			"tier_extended": tierExtendedSchema(),
+			"target_iops":   targetIOPSSchema(),
 			"template_id": {
 				Type:        schema.TypeString,
 				Description: "ID of the template used to create the virtual host.",
@@ -96,11 +146,75 @@ func ResourceVirtualHost() *schema.Resource {
 				Description: "Data protection policy.",
 				Required:    true,
 			},
+			"anti_affinity": {
+				Type:        schema.TypeString,
+				Description: "Anti-affinity group. Updated in place. When not set, the current value is only read.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"business_service": {
+				Type:        schema.TypeString,
+				Description: "Business service. Updated in place. When not set, the current value is only read.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"dedicated_cluster": {
+				Type:        schema.TypeString,
+				Description: "Dedicated cluster. When not set, the cluster chosen by the API is read back. Changing this forces a new virtual host.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"dedicated_dr_cluster": {
+				Type:        schema.TypeString,
+				Description: "Dedicated DR cluster. When not set, the cluster chosen by the API is read back. Changing this forces a new virtual host.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"cluster_type": {
+				Type:        schema.TypeString,
+				Description: "Cluster type, e.g. `PRIMARY`. When not set, the API default is read back. Changing this forces a new virtual host.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Description: "Deployment version. When not set, the API default is read back. Changing this forces a new virtual host.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
 			"interfaces": {
-				Type:     schema.TypeList,
-				Required: true,
+				Type:        schema.TypeList,
+				Description: "Network interfaces, in order. At least one is required. Interfaces are added, changed and removed in place.",
+				Required:    true,
+				MinItems:    1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "ID of the network interface.",
+							Computed:    true,
+						},
+						"label": {
+							Type:        schema.TypeString,
+							Description: "Label of the network interface (NIC).",
+							Computed:    true,
+						},
+						"mac_address": {
+							Type:        schema.TypeString,
+							Description: "MAC address of the network interface.",
+							Computed:    true,
+						},
+						"ipv4_addresses": ipAddressSchema("IPv4 addresses assigned to the network interface."),
+						"ipv6_addresses": ipAddressSchema("IPv6 addresses assigned to the network interface."),
+						"start_connected": {
+							Type:        schema.TypeBool,
+							Description: "Whether the network interface is connected when the virtual host starts.",
+							Computed:    true,
+						},
 						"network_id": {
 							Type:        schema.TypeString,
 							Description: "ID of the network for this interface.",
@@ -120,6 +234,53 @@ func ResourceVirtualHost() *schema.Resource {
 					},
 				},
 			},
+			"disk": {
+				Type:        schema.TypeList,
+				Description: "Additional data disks, in order. Disks are attached, grown, moved to another tier and detached in place. When no `disk` blocks are configured, existing data disks are only read and never detached.",
+				Optional:    true,
+				// Computed keeps disk management opt-in: without disk blocks in the configuration,
+				// data disks created outside Terraform (or before disk support) are read back
+				// without being planned for detachment.
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "ID of the disk.",
+							Computed:    true,
+						},
+						"size_gb": {
+							Type:         schema.TypeInt,
+							Description:  "Disk size in GB. Disks can grow but not shrink.",
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+						"tier_id": {
+							Type:        schema.TypeString,
+							Description: "ID of the storage tier of the disk. Defaults to the tier chosen by the API.",
+							Optional:    true,
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"power_state":         powerStateSchema(),
+			"deletion_protection": deletionProtectionSchema(),
+			"wait_for_state": {
+				Type:        schema.TypeString,
+				Description: "Status to wait for after create, e.g. `RUNNING`. When empty, create returns as soon as the API accepts the request.",
+				Optional:    true,
+			},
+			"primary_ip": {
+				Type:        schema.TypeString,
+				Description: "First IPv4 address of the virtual host, or the first IPv6 address when there is no IPv4 address.",
+				Computed:    true,
+			},
+			"resize_restart_required": {
+				Type:        schema.TypeBool,
+				Description: "Whether the most recently planned resize restarts the virtual host, based on its power state and CPU / memory hot-add settings.",
+				Computed:    true,
+			},
 			"uuid": {
 				Type:        schema.TypeString,
 				Description: "Uuid.",
@@ -148,28 +309,30 @@ mutation CreateVm($input: VirtualHostCreateInput!) {
         coresPerSocket
         memorySizeMB
         tier { id }
+        isExtended
+        targetIops
         domain { id }
         template { id }
         project { id }
         customer { id }
         region
+        networkInterfaceList {
+          id
+          label
+          macAddress
+          network { id }
+          ipv4Addresses { ip prefixlen }
+          ipv6Addresses { ip prefixlen }
+          startConnected
+        }
+        diskList {
+          id
+          sizeGB
+          systemDisk
+          tier { id }
+        }
       }
-    }
-    ... on ValidationErrors {
-      message
-      errors {
-        field
-        messages
-      }
-    }
-    ... on Unauthorized {
-      message
-    }
-    ... on OperationUnavailable {
-      message
-      reasons
-    }
-  }
+    }` + payloadErrorSelection + `  }
 }
 `
 
@@ -184,26 +347,7 @@ func ResourceVirtualHostCreate(ctx context.Context, d *schema.ResourceData, meta
 	}
 	var ifaces []map[string]interface{}
 	for _, raw := range rawIfaces {
-		m := raw.(map[string]interface{})
-
-		iface := map[string]interface{}{
-			"network": m["network_id"].(string),
-		}
-
-		// autoAssignIp
-		if v, ok := m["auto_assign_ip"]; ok {
-			iface["autoAssignIp"] = v.(bool)
-		}
-
-		// ip -> ipList (single element)
-		if ipRaw, ok := m["ip"]; ok {
-			ip := ipRaw.(string)
-			if ip != "" {
-				iface["ipList"] = []string{ip}
-			}
-		}
-
-		ifaces = append(ifaces, iface)
+		ifaces = append(ifaces, interfaceInput(raw.(map[string]interface{})))
 	}
 
 	// Build VirtualHostCreateInput according to the API schema expected by virtualHostCreate.
@@ -222,6 +366,16 @@ func ResourceVirtualHostCreate(ctx context.Context, d *schema.ResourceData, meta
 		"dataProtectionPolicy": d.Get("data_protection_policy").(string),
 		"interfaceList":        ifaces,
 	}
+	addTierOptions(d, input)
+	addPlacementOptions(d, input)
+
+	if rawDisks := d.Get("disk").([]interface{}); len(rawDisks) > 0 {
+		disks := make([]map[string]interface{}, 0, len(rawDisks))
+		for _, raw := range rawDisks {
+			disks = append(disks, diskInput(raw.(map[string]interface{})))
+		}
+		input["diskList"] = disks
+	}
 
 	vars := map[string]interface{}{
 		"input": input,
@@ -237,85 +391,118 @@ func ResourceVirtualHostCreate(ctx context.Context, d *schema.ResourceData, meta
 		CoresPerSocket int                 `json:"coresPerSocket"`
 		MemorySizeMB   int                 `json:"memorySizeMB"`
 		Tier           struct{ ID string } `json:"tier"`
+		IsExtended     bool                `json:"isExtended"`
+		TargetIOPS     *int                `json:"targetIops"`
 		Domain         struct{ ID string } `json:"domain"`
 		Template       struct{ ID string } `json:"template"`
 		Project        struct{ ID string } `json:"project"`
 		Customer       struct{ ID string } `json:"customer"`
 		Region         string              `json:"region"`
-	}
 
-	type fieldMessages struct {
-		Field    string   `json:"field"`
-		Messages []string `json:"messages"`
+		NetworkInterfaceList []apiNetworkInterface `json:"networkInterfaceList"`
+		DiskList             []apiDisk             `json:"diskList"`
 	}
 
-	type virtualHostCreatePayload struct {
-		Typename    string          `json:"__typename"`
-		VirtualHost *virtualHost    `json:"virtualHost,omitempty"`
-		Message     string          `json:"message,omitempty"`
-		Errors      []fieldMessages `json:"errors,omitempty"`
-		Reasons     []string        `json:"reasons,omitempty"`
+	type virtualHostCreated struct {
+		VirtualHost *virtualHost `json:"virtualHost"`
 	}
 
-	var createResp struct {
-		VirtualHostCreate virtualHostCreatePayload `json:"virtualHostCreate"`
+	created, err := ocpclient.Mutate[virtualHostCreated](ctx, client, mutationCreateVM, vars, "virtualHostCreate", "VirtualHostCreated")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
-
-	if err := client.Do(mutationCreateVM, vars, &createResp); err != nil {
-		return diag.FromErr(err)
+	if created.VirtualHost == nil {
+		return diag.Errorf("virtualHostCreate: backend returned VirtualHostCreated without virtualHost")
 	}
 
-	payload := createResp.VirtualHostCreate
+	vm := created.VirtualHost
+
+	d.SetId(vm.ID)
+	_ = d.Set("uuid", vm.UUID)
+	_ = d.Set("hostname", vm.Hostname)
+	_ = d.Set("cpu_count", vm.CpuCount)
+	_ = d.Set("cores_per_socket", vm.CoresPerSocket)
+	_ = d.Set("memory_size_gb", vm.MemorySizeMB/1024)
+	_ = d.Set("status", vm.State)
+	_ = d.Set("project_id", vm.Project.ID)
+	_ = d.Set("customer_id", vm.Customer.ID)
+	_ = d.Set("domain_id", vm.Domain.ID)
+	_ = d.Set("tier_id", vm.Tier.ID)
+	_ = d.Set("tier_extended", vm.IsExtended)
+	if vm.TargetIOPS != nil {
+		_ = d.Set("target_iops", *vm.TargetIOPS)
+	}
+	_ = d.Set("template_id", vm.Template.ID)
+	_ = d.Set("region", vm.Region)
+	if len(vm.NetworkInterfaceList) > 0 {
+		_ = d.Set("interfaces", flattenInterfaces(rawIfaces, vm.NetworkInterfaceList))
+		_ = d.Set("primary_ip", primaryIP(vm.NetworkInterfaceList))
+	}
+	if len(vm.DiskList) > 0 {
+		_ = d.Set("disk", flattenDisks(vm.DiskList))
+	}
 
-	switch payload.Typename {
-	case "VirtualHostCreated":
-		if payload.VirtualHost == nil {
-			return diag.Errorf("virtualHostCreate: backend returned VirtualHostCreated without virtualHost")
+	// The ID is already set, so a failed wait leaves the resource tainted.
+	if target := d.Get("wait_for_state").(string); target != "" {
+		state, diags := waitForVirtualHostState(ctx, client, vm.ID, target)
+		_ = d.Set("status", state)
+		if diags.HasError() {
+			return diags
 		}
+	}
 
-		vm := payload.VirtualHost
-
-		d.SetId(vm.ID)
-		_ = d.Set("uuid", vm.UUID)
-		_ = d.Set("hostname", vm.Hostname)
-		_ = d.Set("cpu_count", vm.CpuCount)
-		_ = d.Set("cores_per_socket", vm.CoresPerSocket)
-		_ = d.Set("memory_size_gb", vm.MemorySizeMB/1024)
-		_ = d.Set("status", vm.State)
-		_ = d.Set("project_id", vm.Project.ID)
-		_ = d.Set("customer_id", vm.Customer.ID)
-		_ = d.Set("domain_id", vm.Domain.ID)
-		_ = d.Set("tier_id", vm.Tier.ID)
-		_ = d.Set("template_id", vm.Template.ID)
-		_ = d.Set("region", vm.Region)
+	// New virtual hosts start powered on.
+	if state, ok := d.GetOk("power_state"); ok && state.(string) != powerStateOn {
+		if diags := setVirtualHostPowerState(ctx, client, vm.ID, state.(string)); diags.HasError() {
+			return diags
+		}
+	}
 
-		return nil
+	return nil
+}
 
-	case "ValidationErrors":
-		// Create returns a union payload; on success we use the returned virtualHost directly (no follow-up listing).
-		msg := payload.Message
-		if len(payload.Errors) > 0 {
-			var details string
-			for _, e := range payload.Errors {
-				details += fmt.Sprintf("%s: %v; ", e.Field, e.Messages)
-			}
-			msg = fmt.Sprintf("%s (%s)", msg, details)
+// waitForVirtualHostState polls the virtual host until its status matches target (case-insensitive).
+// It returns the last observed status. A failed provisioning status or a vanished virtual host
+// ends the wait with an error; the deadline of ctx bounds it.
+func waitForVirtualHostState(ctx context.Context, client *ocpclient.Client, id, target string) (string, diag.Diagnostics) {
+	state := "unknown"
+	err := client.Poll(ctx, func(ctx context.Context) (bool, error) {
+		var resp struct {
+			VirtualHost *struct {
+				State string `json:"state"`
+			} `json:"virtualHost"`
 		}
-		if msg == "" {
-			msg = "validation failed without message"
+		if err := client.DoContext(ctx, queryGetVMState, map[string]interface{}{"id": id}, &resp); err != nil {
+			return false, err
 		}
-		return diag.Errorf("virtualHostCreate: %s", msg)
-
-	case "Unauthorized", "OperationUnavailable":
-		msg := payload.Message
-		if msg == "" {
-			msg = fmt.Sprintf("%s", payload.Typename)
+		if resp.VirtualHost == nil {
+			return false, fmt.Errorf("virtual host %s disappeared while waiting for status %s", id, target)
 		}
-		return diag.Errorf("virtualHostCreate: %s", msg)
 
-	default:
-		return diag.Errorf("virtualHostCreate: unexpected payload type %q", payload.Typename)
+		state = resp.VirtualHost.State
+		switch {
+		case strings.EqualFold(state, target):
+			return true, nil
+		case isFailedVirtualHostState(state):
+			return false, fmt.Errorf("provisioning of virtual host %s failed with status %s", id, state)
+		default:
+			return false, nil
+		}
+	})
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out waiting for virtual host %s to reach status %s (last status %s): %w", id, target, state, context.DeadlineExceeded)
+		}
+		return state, diagnostics.FromErr(err)
 	}
+
+	return state, nil
+}
+
+// isFailedVirtualHostState reports whether a virtual host status denotes failed provisioning.
+func isFailedVirtualHostState(state string) bool {
+	state = strings.ToUpper(state)
+	return strings.Contains(state, "FAIL") || strings.Contains(state, "ERROR")
 }
 
 const queryGetVM = `
@@ -325,18 +512,30 @@ query GetVm($id: GlobalID!) {
     uuid
     hostname
     state
+    powerState
     cpuCount
     coresPerSocket
     memorySizeMB
     note
     dataProtectionPolicy { id note }
     networkInterfaceList {
+      id
+      label
+      macAddress
       network { id }
       ipv4Addresses { ip prefixlen }
       ipv6Addresses { ip prefixlen }
       startConnected
     }
+    diskList {
+      id
+      sizeGB
+      systemDisk
+      tier { id }
+    }
     tier { id }
+    isExtended
+    targetIops
     domain { id }
     template { id }
     project { id }
@@ -346,6 +545,17 @@ query GetVm($id: GlobalID!) {
 }
 `
 
+// queryGetVMState only selects the status, so that polling while waiting for provisioning or
+// deletion does not depend on the rest of the VirtualHostNode selection.
+const queryGetVMState = `
+query GetVmState($id: GlobalID!) {
+  virtualHost(id: $id) {
+    id
+    state
+  }
+}
+`
+
 // ResourceVirtualHostRead refreshes Terraform state from the API.
 func ResourceVirtualHostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*ocpclient.Client)
@@ -358,6 +568,7 @@ func ResourceVirtualHostRead(ctx context.Context, d *schema.ResourceData, meta i
 			UUID                 string `json:"uuid"`
 			Hostname             string `json:"hostname"`
 			State                string `json:"state"`
+			PowerState           string `json:"powerState"`
 			CpuCount             int    `json:"cpuCount"`
 			CoresPerSocket       int    `json:"coresPerSocket"`
 			MemorySizeMB         int    `json:"memorySizeMB"`
@@ -366,29 +577,26 @@ func ResourceVirtualHostRead(ctx context.Context, d *schema.ResourceData, meta i
 				ID   string `json:"id"`
 				Note string `json:"note"`
 			} `json:"dataProtectionPolicy"`
-			NetworkInterfaceList []struct {
-				Network       struct{ ID string } `json:"network"`
-				IPv4Addresses []struct {
-					IP        string `json:"ip"`
-					Prefixlen int    `json:"prefixlen"`
-				} `json:"ipv4Addresses"`
-				IPv6Addresses []struct {
-					IP        string `json:"ip"`
-					Prefixlen int    `json:"prefixlen"`
-				} `json:"ipv6Addresses"`
-				StartConnected bool `json:"startConnected"`
-			} `json:"networkInterfaceList"`
-			Tier     struct{ ID string } `json:"tier"`
-			Domain   struct{ ID string } `json:"domain"`
-			Template struct{ ID string } `json:"template"`
-			Project  struct{ ID string } `json:"project"`
-			Customer struct{ ID string } `json:"customer"`
-			Region   string              `json:"region"`
+			NetworkInterfaceList []apiNetworkInterface `json:"networkInterfaceList"`
+			DiskList             []apiDisk             `json:"diskList"`
+			Tier                 struct{ ID string }   `json:"tier"`
+			IsExtended           bool                  `json:"isExtended"`
+			TargetIOPS           *int                  `json:"targetIops"`
+			Domain               struct{ ID string }   `json:"domain"`
+			Template             struct{ ID string }   `json:"template"`
+			Project              struct{ ID string }   `json:"project"`
+			Customer             struct{ ID string }   `json:"customer"`
+			Region               string                `json:"region"`
 		} `json:"virtualHost"`
 	}
 
-	if err := client.Do(queryGetVM, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	if err := client.DoContext(ctx, queryGetVM, vars, &resp); err != nil {
+		// The VM was deleted outside Terraform; drop it from state so it gets recreated.
+		if ocpclient.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diagnostics.FromErr(err)
 	}
 
 	if resp.VirtualHost == nil {
@@ -398,18 +606,10 @@ func ResourceVirtualHostRead(ctx context.Context, d *schema.ResourceData, meta i
 
 	vh := resp.VirtualHost
 
-	// Map API network interfaces into Terraform "interfaces" blocks.
-	//
-	// Provider schema:
-	// - network_id (required)
-	// - auto_assign_ip (optional, default true)
-	// - ip (optional)
-	//
-	// API exposes networkInterfaceList with a list of ipv4Addresses. For import / state refresh we
-	// use a pragmatic mapping:
 	_ = d.Set("uuid", vh.UUID)
 	_ = d.Set("hostname", vh.Hostname)
 	_ = d.Set("status", vh.State)
+	_ = d.Set("power_state", flattenPowerState(vh.PowerState))
 	_ = d.Set("cpu_count", vh.CpuCount)
 	_ = d.Set("cores_per_socket", vh.CoresPerSocket)
 	_ = d.Set("memory_size_gb", vh.MemorySizeMB/1024)
@@ -418,56 +618,31 @@ func ResourceVirtualHostRead(ctx context.Context, d *schema.ResourceData, meta i
 	// The backend doesn't store it on the VirtualHost object, so during Read() we keep whatever value Terraform
 	// already has (config/state), falling back to the schema default when not set (e.g., during import).
 	_ = d.Set("allow_resize_restart", d.Get("allow_resize_restart").(bool))
+	_ = d.Set("deletion_protection", d.Get("deletion_protection").(bool))
 	_ = d.Set("note", vh.Note)
 	if vh.DataProtectionPolicy != nil {
 		_ = d.Set("data_protection_policy", vh.DataProtectionPolicy.ID)
 	}
 	_ = d.Set("tier_id", vh.Tier.ID)
+	_ = d.Set("tier_extended", vh.IsExtended)
+	_ = d.Set("target_iops", flattenTargetIOPS(vh.TargetIOPS))
 	_ = d.Set("domain_id", vh.Domain.ID)
 	_ = d.Set("template_id", vh.Template.ID)
 	_ = d.Set("project_id", vh.Project.ID)
 	_ = d.Set("customer_id", vh.Customer.ID)
 	_ = d.Set("region", vh.Region)
-
-	// Map backend NetworkInterface objects to Terraform "interfaces" blocks.
-	//
-	// Important: the platform doesn't provide enough information to reliably distinguish
-	// "static IP" vs "DHCP" for every VM in all states. Also, we currently don't implement
-	// in-place updates for interfaces.
-	//
-	// To make imports and `terraform plan -generate-config-out=...` work, we only populate
-	// `interfaces` from the API when Terraform doesn't already have interfaces in state/config
-	// (typical during import). If the user already manages `interfaces` in their HCL, we leave
-	// it untouched to avoid spurious diffs.
-	if current, ok := d.Get("interfaces").([]interface{}); ok && len(current) == 0 {
-		ifaces := make([]interface{}, 0, len(vh.NetworkInterfaceList))
-		for _, ni := range vh.NetworkInterfaceList {
-			m := map[string]interface{}{
-				"network_id": ni.Network.ID,
-			}
-
-			// Heuristic mapping:
-			// - if backend returns at least one IPv4 address, treat it as a manually assigned IP
-			//   (auto_assign_ip=false) and persist the first address into "ip".
-			// - otherwise, keep auto_assign_ip=true and omit "ip".
-			if len(ni.IPv4Addresses) > 0 {
-				m["auto_assign_ip"] = false
-				m["ip"] = ni.IPv4Addresses[0].IP
-			} else {
-				m["auto_assign_ip"] = true
-			}
-			ifaces = append(ifaces, m)
-		}
-		_ = d.Set("interfaces", ifaces)
+	if diags := readPlacement(ctx, d, client); diags.HasError() {
+		return diags
 	}
 
-	return nil
-}
+	// Always map interfaces from the API so that NICs added, removed or changed outside
+	// Terraform show up as drift. See flattenInterfaces for how auto_assign_ip is derived.
+	current, _ := d.Get("interfaces").([]interface{})
+	_ = d.Set("interfaces", flattenInterfaces(current, vh.NetworkInterfaceList))
+	_ = d.Set("primary_ip", primaryIP(vh.NetworkInterfaceList))
+	_ = d.Set("disk", flattenDisks(vh.DiskList))
 
-// Create returns a union payload; on success we use the returned virtualHost directly (no follow-up listing).
-type gqlFieldError struct {
-	Field    string   `json:"field"`
-	Messages []string `json:"messages"`
+	return nil
 }
 
 const mutationResizeVm = `
@@ -476,22 +651,7 @@ mutation ResizeVm($input: VirtualHostResizeInput!) {
     __typename
     ... on TaskExecutionNode {
       id
-    }
-    ... on ValidationErrors {
-      message
-      errors {
-        field
-        messages
-      }
-    }
-    ... on Unauthorized {
-      message
-    }
-    ... on OperationUnavailable {
-      message
-      reasons
-    }
-  }
+    }` + payloadErrorSelection + `  }
 }
 `
 
@@ -501,202 +661,261 @@ mutation UpdateVmTier($input: VirtualHostUpdateTierInput!) {
     __typename
     ... on TaskExecutionNode {
       id
-    }
-    ... on ValidationErrors {
-      message
-      errors {
-        field
-        messages
-      }
-    }
-    ... on Unauthorized {
-      message
-    }
-    ... on OperationUnavailable {
-      message
-      reasons
-    }
-  }
+    }` + payloadErrorSelection + `  }
 }
 `
 
-// ResourceVirtualHostUpdate updates either sizing or tier; applying both in one run is rejected.
-func ResourceVirtualHostUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*ocpclient.Client)
-
-	// Create returns a union payload; on success we use the returned virtualHost directly (no follow-up listing).
-	sizingChanged := d.HasChange("cpu_count") || d.HasChange("cores_per_socket") || d.HasChange("memory_size_gb")
-	tierChanged := d.HasChange("tier_id")
-
-	changeGroups := 0
-	if sizingChanged {
-		changeGroups++
+// waitForTask blocks until the task started by operation reaches a terminal state.
+// The wait is bounded by the deadline of ctx, which Terraform derives from the resource Timeouts.
+func waitForTask(ctx context.Context, client *ocpclient.Client, operation, taskID string) diag.Diagnostics {
+	if taskID == "" {
+		return diag.Errorf("%s: backend returned TaskExecutionNode without id", operation)
 	}
-	if tierChanged {
-		changeGroups++
+	if _, err := client.WaitForTask(ctx, taskID); err != nil {
+		return diagnostics.FromErr(fmt.Errorf("%s: %w", operation, err))
 	}
+	return nil
+}
 
-	// Create returns a union payload; on success we use the returned virtualHost directly (no follow-up listing).
-	if changeGroups == 0 {
-		return ResourceVirtualHostRead(ctx, d, meta)
+// runTaskMutation executes a mutation whose success branch is a TaskExecutionNode and waits for the task.
+func runTaskMutation(ctx context.Context, client *ocpclient.Client, query, operation string, input map[string]interface{}) diag.Diagnostics {
+	task, err := ocpclient.Mutate[ocpclient.TaskExecution](ctx, client, query, map[string]interface{}{
+		"input": input,
+	}, operation, ocpclient.TypenameTaskExecutionNode)
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
+	return waitForTask(ctx, client, operation, task.ID)
+}
 
-	// Simultaneous sizing and tier changes are not supported in a single apply.
-	if changeGroups > 1 {
-		return diag.Errorf(
-			"ocp_virtual_host: simultaneous change of sizing (cpu_count/cores_per_socket/memory_size_gb) and tier_id in a single apply is not supported. " +
-				"Please apply sizing changes first, wait for the job to finish, and then apply tier change in a separate terraform apply.",
-		)
-	}
+// virtualHostUpdateStep is one change group applied by the update functions.
+type virtualHostUpdateStep struct {
+	// keys are the attributes handled by the step; it runs only when one of them changed.
+	keys  []string
+	apply func(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics
+}
 
-	// 1) resize – cpu_count / cores_per_socket / memory_size_gb
-	if sizingChanged {
-		input := map[string]interface{}{
-			"virtualHost":  d.Id(),
-			"allowRestart": d.Get("allow_resize_restart").(bool),
-		}
+// virtualHostSizingStep and virtualHostTierStep are shared by ocp_virtual_host and ocp_virtual_host_immutable.
+var (
+	virtualHostSizingStep = virtualHostUpdateStep{
+		keys:  []string{"cpu_count", "cores_per_socket", "memory_size_gb"},
+		apply: resizeVirtualHost,
+	}
+	virtualHostTierStep = virtualHostUpdateStep{
+		keys:  []string{"tier_id", "tier_extended", "target_iops"},
+		apply: updateVirtualHostTier,
+	}
+)
 
-		if d.HasChange("cpu_count") {
-			input["cpuCount"] = d.Get("cpu_count").(int)
-		}
-		if d.HasChange("cores_per_socket") {
-			input["coresPerSocket"] = d.Get("cores_per_socket").(int)
-		}
-		if d.HasChange("memory_size_gb") {
-			input["memorySizeGB"] = d.Get("memory_size_gb").(int)
-		}
+// virtualHostSettingsStep changes the note, data protection policy and in-place placement
+// options of ocp_virtual_host.
+var virtualHostSettingsStep = virtualHostUpdateStep{
+	keys:  []string{"note", "data_protection_policy", "anti_affinity", "business_service"},
+	apply: updateVirtualHostSettings,
+}
 
-		var respResize struct {
-			VirtualHostResize struct {
-				Typename string          `json:"__typename"`
-				Message  string          `json:"message,omitempty"`
-				Errors   []gqlFieldError `json:"errors,omitempty"`
-				Reasons  []string        `json:"reasons,omitempty"`
-			} `json:"virtualHostResize"`
-		}
+// virtualHostUpdateSteps lists, in order, every change group ResourceVirtualHostUpdate applies.
+// Attributes that are neither ForceNew, Terraform-only nor covered here would silently no-op.
+var virtualHostUpdateSteps = []virtualHostUpdateStep{
+	virtualHostSizingStep,
+	virtualHostTierStep,
+	virtualHostSettingsStep,
+	virtualHostInterfacesStep,
+	virtualHostDisksStep,
+	virtualHostPowerStep,
+}
 
-		if err := client.Do(mutationResizeVm, map[string]interface{}{
-			"input": input,
-		}, &respResize); err != nil {
-			return diag.FromErr(err)
+// applyUpdateSteps runs the steps in order, each one waiting for its task before the next starts.
+//
+// When a step fails, its attributes and those of all later steps are reverted to their prior
+// values, so Terraform records the steps that completed and plans the rest again.
+func applyUpdateSteps(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client, steps ...virtualHostUpdateStep) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, step := range steps {
+		if !d.HasChanges(step.keys...) {
+			continue
 		}
-
-		p := respResize.VirtualHostResize
-
-		switch p.Typename {
-		case "TaskExecutionNode":
-			// OK: job started.
-
-		case "ValidationErrors":
-			msg := p.Message
-			for _, e := range p.Errors {
-				msg += fmt.Sprintf(" %s: %v;", e.Field, e.Messages)
-			}
-			if msg == "" {
-				msg = "validation failed without message"
+		diags = append(diags, step.apply(ctx, d, client)...)
+		if diags.HasError() {
+			for _, pending := range steps[i:] {
+				for _, key := range pending.keys {
+					old, _ := d.GetChange(key)
+					_ = d.Set(key, old)
+				}
 			}
-			return diag.Errorf("virtualHostResize: %s", msg)
-
-		case "Unauthorized", "OperationUnavailable":
-			msg := p.Message
-			if len(p.Reasons) > 0 {
-				msg = fmt.Sprintf("%s (reasons=%v)", msg, p.Reasons)
-			}
-			if msg == "" {
-				msg = p.Typename
-			}
-			return diag.Errorf("virtualHostResize: %s", msg)
-
-		default:
-			return diag.Errorf("virtualHostResize: unexpected payload type %q", p.Typename)
+			return diags
 		}
 	}
+	return diags
+}
 
-	// Tier change (tier_id).
-	if tierChanged {
-		input := map[string]interface{}{
-			"virtualHost": d.Id(),
-			"tier":        d.Get("tier_id").(string),
-			// isExtended / targetIops are currently left at API defaults.
-		}
+// resizeVirtualHost changes cpu_count / cores_per_socket / memory_size_gb and waits for the resize task.
+func resizeVirtualHost(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics {
+	input := map[string]interface{}{
+		"virtualHost":  d.Id(),
+		"allowRestart": d.Get("allow_resize_restart").(bool),
+	}
 
-		var respTier struct {
-			VirtualHostUpdateTier struct {
-				Typename string          `json:"__typename"`
-				Message  string          `json:"message,omitempty"`
-				Errors   []gqlFieldError `json:"errors,omitempty"`
-				Reasons  []string        `json:"reasons,omitempty"`
-			} `json:"virtualHostUpdateTier"`
-		}
+	if d.HasChange("cpu_count") {
+		input["cpuCount"] = d.Get("cpu_count").(int)
+	}
+	if d.HasChange("cores_per_socket") {
+		input["coresPerSocket"] = d.Get("cores_per_socket").(int)
+	}
+	if d.HasChange("memory_size_gb") {
+		input["memorySizeGB"] = d.Get("memory_size_gb").(int)
+	}
 
-		if err := client.Do(mutationUpdateVmTier, map[string]interface{}{
-			"input": input,
-		}, &respTier); err != nil {
-			return diag.FromErr(err)
-		}
+	diags := runTaskMutation(ctx, client, mutationResizeVm, "virtualHostResize", input)
+	if !diags.HasError() && d.Get("resize_restart_required").(bool) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Resize may have restarted the virtual host",
+			Detail:   fmt.Sprintf("The plan determined that resizing virtual host %s requires a restart, so the resize may have restarted it.", d.Id()),
+		})
+	}
+	return diags
+}
 
-		p := respTier.VirtualHostUpdateTier
+// updateVirtualHostTier changes tier_id, tier_extended and target_iops and waits for the tier change task.
+func updateVirtualHostTier(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics {
+	input := map[string]interface{}{
+		"virtualHost": d.Id(),
+		"tier":        d.Get("tier_id").(string),
+	}
+	addTierOptions(d, input)
 
-		switch p.Typename {
-		case "TaskExecutionNode":
-			// OK: job started.
+	return runTaskMutation(ctx, client, mutationUpdateVmTier, "virtualHostUpdateTier", input)
+}
 
-		case "ValidationErrors":
-			msg := p.Message
-			for _, e := range p.Errors {
-				msg += fmt.Sprintf(" %s: %v;", e.Field, e.Messages)
-			}
-			if msg == "" {
-				msg = "validation failed without message"
-			}
-			return diag.Errorf("virtualHostUpdateTier: %s", msg)
+const mutationUpdateVM = `
+mutation UpdateVm($input: VirtualHostUpdateInput!) {
+  virtualHostUpdate(input: $input) {
+    __typename
+    ... on VirtualHostNode {
+      id
+    }
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
 
-		case "Unauthorized", "OperationUnavailable":
-			msg := p.Message
-			if len(p.Reasons) > 0 {
-				msg = fmt.Sprintf("%s (reasons=%v)", msg, p.Reasons)
-			}
-			if msg == "" {
-				msg = p.Typename
-			}
-			return diag.Errorf("virtualHostUpdateTier: %s", msg)
+// updateVirtualHostSettings changes note / data_protection_policy. The API either applies the
+// change immediately (VirtualHostNode) or starts a task, which is awaited.
+func updateVirtualHostSettings(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics {
+	input := map[string]interface{}{
+		"virtualHost": d.Id(),
+	}
+	if d.HasChange("note") {
+		input["note"] = d.Get("note").(string)
+	}
+	if d.HasChange("data_protection_policy") {
+		input["dataProtectionPolicy"] = d.Get("data_protection_policy").(string)
+	}
+	if d.HasChange("anti_affinity") {
+		input["antiAffinity"] = d.Get("anti_affinity").(string)
+	}
+	if d.HasChange("business_service") {
+		input["businessService"] = d.Get("business_service").(string)
+	}
 
-		default:
-			return diag.Errorf("virtualHostUpdateTier: unexpected payload type %q", p.Typename)
-		}
+	type updated struct {
+		Typename string `json:"__typename"`
+		ID       string `json:"id"`
 	}
 
-	// isExtended / targetIops are currently left at API defaults.
-	return ResourceVirtualHostRead(ctx, d, meta)
+	p, err := ocpclient.Mutate[updated](ctx, client, mutationUpdateVM, map[string]interface{}{
+		"input": input,
+	}, "virtualHostUpdate", "VirtualHostNode", ocpclient.TypenameTaskExecutionNode)
+	if err != nil {
+		return diagnostics.FromErr(err)
+	}
+	if p.Typename == ocpclient.TypenameTaskExecutionNode {
+		return waitForTask(ctx, client, "virtualHostUpdate", p.ID)
+	}
+	return nil
 }
 
-// ResourceVirtualHostDelete deletes the virtual host and clears Terraform state.
-func ResourceVirtualHostDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// ResourceVirtualHostUpdate applies the change groups in virtualHostUpdateSteps in order.
+func ResourceVirtualHostUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*ocpclient.Client)
 
-	const mutationDelete = `
+	diags := applyUpdateSteps(ctx, d, client, virtualHostUpdateSteps...)
+	if diags.HasError() {
+		return diags
+	}
+
+	return append(diags, ResourceVirtualHostRead(ctx, d, meta)...)
+}
+
+const mutationDeleteVM = `
 mutation DeleteVm($input: VirtualHostDeleteInput!) {
   virtualHostDelete(input: $input) {
     __typename
-  }
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
 }
 `
 
+// ResourceVirtualHostDelete deletes the virtual host, waits for the deletion task and
+// confirms the virtual host is gone before clearing Terraform state.
+func ResourceVirtualHostDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := checkDeletionProtection(d); diags.HasError() {
+		return diags
+	}
+
+	client := meta.(*ocpclient.Client)
+
 	vars := map[string]interface{}{
 		"input": map[string]interface{}{
 			"virtualHost": d.Id(),
-			// isExtended / targetIops are currently left at API defaults.
 		},
 	}
 
-	if err := client.Do(mutationDelete, vars, &struct {
-		VirtualHostDelete struct {
-			Typename string `json:"__typename"`
-		} `json:"virtualHostDelete"`
-	}{}); err != nil {
-		return diag.FromErr(err)
+	task, err := ocpclient.Mutate[ocpclient.TaskExecution](ctx, client, mutationDeleteVM, vars, "virtualHostDelete", ocpclient.TypenameTaskExecutionNode)
+	if err != nil {
+		return diagnostics.FromErr(err)
+	}
+	if diags := waitForTask(ctx, client, "virtualHostDelete", task.ID); diags.HasError() {
+		return diags
+	}
+	if diags := waitForVirtualHostGone(ctx, client, d.Id()); diags.HasError() {
+		return diags
 	}
 
 	d.SetId("")
 	return nil
 }
+
+// waitForVirtualHostGone polls until virtualHost(id) resolves to null.
+// The deadline of ctx bounds the wait.
+func waitForVirtualHostGone(ctx context.Context, client *ocpclient.Client, id string) diag.Diagnostics {
+	state := "unknown"
+	err := client.Poll(ctx, func(ctx context.Context) (bool, error) {
+		var resp struct {
+			VirtualHost *struct {
+				State string `json:"state"`
+			} `json:"virtualHost"`
+		}
+		if err := client.DoContext(ctx, queryGetVMState, map[string]interface{}{"id": id}, &resp); err != nil {
+			if ocpclient.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		if resp.VirtualHost == nil {
+			return true, nil
+		}
+		state = resp.VirtualHost.State
+		return false, nil
+	})
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out waiting for virtual host %s to be deleted (last status %s): %w", id, state, context.DeadlineExceeded)
+		}
+		return diagnostics.FromErr(err)
+	}
+	return nil
+}
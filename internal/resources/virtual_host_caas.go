@@ -2,12 +2,12 @@ package resources
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // ResourceVirtualHostCaas manages "shadow" VM objects for inventory/accounting.
@@ -20,11 +20,15 @@ func ResourceVirtualHostCaas() *schema.Resource {
 		UpdateContext: resourceVirtualHostCaasUpdate,
 		DeleteContext: resourceVirtualHostCaasDelete,
 
-		// Import expects the VirtualHost GlobalID (same value as resource ID).
-		// Example:
+		// Replacing a shadow object with deletion_protection enabled is rejected at plan time.
+		CustomizeDiff: customizeDeletionProtectionDiff(ResourceVirtualHostCaas),
+
+		// Import accepts the VirtualHost GlobalID (same value as resource ID) or a lookup.
+		// Examples:
 		//   terraform import ocp_virtual_host_caas.shadow "VmlydHVhbEhvc3ROb2RlOjEyMzQ1"
+		//   terraform import ocp_virtual_host_caas.shadow "hostname=app-1,project=project-a"
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importVirtualHost,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -75,6 +79,8 @@ func ResourceVirtualHostCaas() *schema.Resource {
 				Required:    true,
 			},
 
+			"deletion_protection": deletionProtectionSchema(),
+
 			// Convenience computed fields (inventory/UX).
 			"customer_id": {
 				Type:        schema.TypeString,
@@ -90,53 +96,21 @@ func ResourceVirtualHostCaas() *schema.Resource {
 	}
 }
 
-type caasFieldError struct {
-	Field    string   `json:"field"`
-	Messages []string `json:"messages"`
-}
-
-// Common shape for CAAS union payloads. Success branch is VirtualHostNode.
-type caasPayload struct {
-	Typename string           `json:"__typename"`
-	Message  string           `json:"message,omitempty"`
-	Errors   []caasFieldError `json:"errors,omitempty"`
-	Reasons  []string         `json:"reasons,omitempty"`
-
-	// VirtualHostNode fields (only populated on success branch)
-	ID       string `json:"id,omitempty"`
-	UUID     string `json:"uuid,omitempty"`
-	Hostname string `json:"hostname,omitempty"`
-	Note     string `json:"note,omitempty"`
-	State    string `json:"state,omitempty"`
-	Region   string `json:"region,omitempty"`
-
-	Tier struct {
-		ID string `json:"id"`
-	} `json:"tier,omitempty"`
-
-	Project struct {
-		ID string `json:"id"`
-	} `json:"project,omitempty"`
-
-	Customer struct {
-		ID string `json:"id"`
-	} `json:"customer,omitempty"`
-
-	Vcenter struct {
+// caasVirtualHost is the VirtualHostNode success branch of the CAAS mutation payloads.
+type caasVirtualHost struct {
+	ID       string              `json:"id"`
+	UUID     string              `json:"uuid"`
+	Hostname string              `json:"hostname"`
+	Note     string              `json:"note"`
+	State    string              `json:"state"`
+	Region   string              `json:"region"`
+	Tier     struct{ ID string } `json:"tier"`
+	Project  struct{ ID string } `json:"project"`
+	Customer struct{ ID string } `json:"customer"`
+	Vcenter  struct {
 		ID   string `json:"id"`
-		Name string `json:"name,omitempty"`
-	} `json:"vcenter,omitempty"`
-}
-
-func formatValidationMessage(p caasPayload) string {
-	msg := p.Message
-	for _, e := range p.Errors {
-		msg += fmt.Sprintf(" %s: %v;", e.Field, e.Messages)
-	}
-	if msg == "" {
-		msg = "validation failed without message"
-	}
-	return msg
+		Name string `json:"name"`
+	} `json:"vcenter"`
 }
 
 const mutationVirtualHostCreateCaas = `
@@ -154,19 +128,7 @@ mutation CreateVirtualHostCaas($input: VirtualHostCreateCaasInput!) {
       project { id }
       customer { id }
       vcenter { id name }
-    }
-    ... on ValidationErrors {
-      message
-      errors { field messages }
-    }
-    ... on Unauthorized {
-      message
-    }
-    ... on OperationUnavailable {
-      message
-      reasons
-    }
-  }
+    }` + payloadErrorSelection + `  }
 }
 `
 
@@ -185,19 +147,7 @@ mutation UpdateVirtualHostCaas($input: VirtualHostUpdateCaasInput!) {
       project { id }
       customer { id }
       vcenter { id name }
-    }
-    ... on ValidationErrors {
-      message
-      errors { field messages }
-    }
-    ... on Unauthorized {
-      message
-    }
-    ... on OperationUnavailable {
-      message
-      reasons
-    }
-  }
+    }` + payloadErrorSelection + `  }
 }
 `
 
@@ -205,19 +155,7 @@ const mutationVirtualHostDeleteCaas = `
 mutation DeleteVirtualHostCaas($input: VirtualHostDeleteCaasInput!) {
   virtualHostDeleteCaas(input: $input) {
     __typename
-    ... on VirtualHostNode { id }
-    ... on ValidationErrors {
-      message
-      errors { field messages }
-    }
-    ... on Unauthorized {
-      message
-    }
-    ... on OperationUnavailable {
-      message
-      reasons
-    }
-  }
+    ... on VirtualHostNode { id }` + payloadErrorSelection + `  }
 }
 `
 
@@ -251,76 +189,42 @@ func resourceVirtualHostCaasCreate(ctx context.Context, d *schema.ResourceData,
 		"region":   d.Get("region").(string),
 	}
 
-	var resp struct {
-		VirtualHostCreateCaas caasPayload `json:"virtualHostCreateCaas"`
+	p, err := ocpclient.Mutate[caasVirtualHost](ctx, client, mutationVirtualHostCreateCaas, map[string]interface{}{"input": input}, "virtualHostCreateCaas", "VirtualHostNode")
+	if err != nil {
+		return diagnostics.FromErr(err)
 	}
-
-	if err := client.Do(mutationVirtualHostCreateCaas, map[string]interface{}{"input": input}, &resp); err != nil {
-		return diag.FromErr(err)
+	if p.ID == "" {
+		return diag.Errorf("virtualHostCreateCaas: backend returned VirtualHostNode without id")
 	}
+	d.SetId(p.ID)
+
+	_ = d.Set("uuid", p.UUID)
+	_ = d.Set("hostname", p.Hostname)
+	_ = d.Set("note", p.Note)
+	_ = d.Set("status", p.State)
+	_ = d.Set("region", p.Region)
+	_ = d.Set("tier_id", p.Tier.ID)
+	_ = d.Set("project_id", p.Project.ID)
+	_ = d.Set("customer_id", p.Customer.ID)
+	_ = d.Set("vcenter_id", p.Vcenter.ID)
 
-	p := resp.VirtualHostCreateCaas
-
-	switch p.Typename {
-	case "VirtualHostNode":
-		if p.ID == "" {
-			return diag.Errorf("virtualHostCreateCaas: backend returned VirtualHostNode without id")
-		}
-		d.SetId(p.ID)
-
-		_ = d.Set("uuid", p.UUID)
-		_ = d.Set("hostname", p.Hostname)
-		_ = d.Set("note", p.Note)
-		_ = d.Set("status", p.State)
-		_ = d.Set("region", p.Region)
-		_ = d.Set("tier_id", p.Tier.ID)
-		_ = d.Set("project_id", p.Project.ID)
-		_ = d.Set("customer_id", p.Customer.ID)
-		_ = d.Set("vcenter_id", p.Vcenter.ID)
-
-		return nil
-
-	case "ValidationErrors":
-		return diag.Errorf("virtualHostCreateCaas: %s", formatValidationMessage(p))
-
-	case "Unauthorized", "OperationUnavailable":
-		msg := p.Message
-		if len(p.Reasons) > 0 {
-			msg = fmt.Sprintf("%s (reasons=%v)", msg, p.Reasons)
-		}
-		if msg == "" {
-			msg = p.Typename
-		}
-		return diag.Errorf("virtualHostCreateCaas: %s", msg)
-
-	default:
-		return diag.Errorf("virtualHostCreateCaas: unexpected payload type %q", p.Typename)
-	}
+	return nil
 }
 
 func resourceVirtualHostCaasRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*ocpclient.Client)
 
 	var resp struct {
-		VirtualHost *struct {
-			ID       string              `json:"id"`
-			UUID     string              `json:"uuid"`
-			Hostname string              `json:"hostname"`
-			Note     string              `json:"note"`
-			State    string              `json:"state"`
-			Region   string              `json:"region"`
-			Tier     struct{ ID string } `json:"tier"`
-			Project  struct{ ID string } `json:"project"`
-			Customer struct{ ID string } `json:"customer"`
-			Vcenter  struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			} `json:"vcenter"`
-		} `json:"virtualHost"`
+		VirtualHost *caasVirtualHost `json:"virtualHost"`
 	}
 
-	if err := client.Do(queryGetVirtualHostCaas, map[string]interface{}{"id": d.Id()}, &resp); err != nil {
-		return diag.FromErr(err)
+	if err := client.DoContext(ctx, queryGetVirtualHostCaas, map[string]interface{}{"id": d.Id()}, &resp); err != nil {
+		// The VM was deleted outside Terraform; drop it from state so it gets recreated.
+		if ocpclient.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diagnostics.FromErr(err)
 	}
 
 	if resp.VirtualHost == nil {
@@ -340,6 +244,9 @@ func resourceVirtualHostCaasRead(ctx context.Context, d *schema.ResourceData, me
 	_ = d.Set("customer_id", vh.Customer.ID)
 	_ = d.Set("vcenter_id", vh.Vcenter.ID)
 
+	// Terraform-only; keep the configured value, or the default after import.
+	_ = d.Set("deletion_protection", d.Get("deletion_protection").(bool))
+
 	return nil
 }
 
@@ -363,75 +270,29 @@ func resourceVirtualHostCaasUpdate(ctx context.Context, d *schema.ResourceData,
 		input["tier"] = d.Get("tier_id").(string)
 	}
 
-	var resp struct {
-		VirtualHostUpdateCaas caasPayload `json:"virtualHostUpdateCaas"`
-	}
-
-	if err := client.Do(mutationVirtualHostUpdateCaas, map[string]interface{}{"input": input}, &resp); err != nil {
-		return diag.FromErr(err)
+	if _, err := ocpclient.Mutate[caasVirtualHost](ctx, client, mutationVirtualHostUpdateCaas, map[string]interface{}{"input": input}, "virtualHostUpdateCaas", "VirtualHostNode"); err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	p := resp.VirtualHostUpdateCaas
-
-	switch p.Typename {
-	case "VirtualHostNode":
-		// Refresh state
-		return resourceVirtualHostCaasRead(ctx, d, meta)
-
-	case "ValidationErrors":
-		return diag.Errorf("virtualHostUpdateCaas: %s", formatValidationMessage(p))
-
-	case "Unauthorized", "OperationUnavailable":
-		msg := p.Message
-		if len(p.Reasons) > 0 {
-			msg = fmt.Sprintf("%s (reasons=%v)", msg, p.Reasons)
-		}
-		if msg == "" {
-			msg = p.Typename
-		}
-		return diag.Errorf("virtualHostUpdateCaas: %s", msg)
-
-	default:
-		return diag.Errorf("virtualHostUpdateCaas: unexpected payload type %q", p.Typename)
-	}
+	// Refresh state
+	return resourceVirtualHostCaasRead(ctx, d, meta)
 }
 
 func resourceVirtualHostCaasDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := checkDeletionProtection(d); diags.HasError() {
+		return diags
+	}
+
 	client := meta.(*ocpclient.Client)
 
 	input := map[string]interface{}{
 		"virtualHost": d.Id(),
 	}
 
-	var resp struct {
-		VirtualHostDeleteCaas caasPayload `json:"virtualHostDeleteCaas"`
-	}
-
-	if err := client.Do(mutationVirtualHostDeleteCaas, map[string]interface{}{"input": input}, &resp); err != nil {
-		return diag.FromErr(err)
+	if _, err := ocpclient.Mutate[caasVirtualHost](ctx, client, mutationVirtualHostDeleteCaas, map[string]interface{}{"input": input}, "virtualHostDeleteCaas", "VirtualHostNode"); err != nil {
+		return diagnostics.FromErr(err)
 	}
 
-	p := resp.VirtualHostDeleteCaas
-
-	switch p.Typename {
-	case "VirtualHostNode":
-		d.SetId("")
-		return nil
-
-	case "ValidationErrors":
-		return diag.Errorf("virtualHostDeleteCaas: %s", formatValidationMessage(p))
-
-	case "Unauthorized", "OperationUnavailable":
-		msg := p.Message
-		if len(p.Reasons) > 0 {
-			msg = fmt.Sprintf("%s (reasons=%v)", msg, p.Reasons)
-		}
-		if msg == "" {
-			msg = p.Typename
-		}
-		return diag.Errorf("virtualHostDeleteCaas: %s", msg)
-
-	default:
-		return diag.Errorf("virtualHostDeleteCaas: unexpected payload type %q", p.Typename)
-	}
+	d.SetId("")
+	return nil
 }
@@ -0,0 +1,149 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+const mutationCreateDisk = `
+mutation CreateDisk($input: DiskCreateInput!) {
+  diskCreate(input: $input) {
+    __typename
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
+
+const mutationUpdateDisk = `
+mutation UpdateDisk($input: DiskUpdateInput!) {
+  diskUpdate(input: $input) {
+    __typename
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
+
+const mutationDeleteDisk = `
+mutation DeleteDisk($input: DiskDeleteInput!) {
+  diskDelete(input: $input) {
+    __typename
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
+
+// apiDisk is a Disk as returned by the virtualHost query.
+type apiDisk struct {
+	ID         string              `json:"id"`
+	SizeGB     int                 `json:"sizeGB"`
+	SystemDisk bool                `json:"systemDisk"`
+	Tier       struct{ ID string } `json:"tier"`
+}
+
+// virtualHostDisksStep attaches, grows, re-tiers and detaches data disks of ocp_virtual_host.
+var virtualHostDisksStep = virtualHostUpdateStep{
+	keys:  []string{"disk"},
+	apply: updateVirtualHostDisks,
+}
+
+// diskConfigKeys are the configurable attributes of a "disk" block.
+var diskConfigKeys = []string{"size_gb", "tier_id"}
+
+// diskInput maps a "disk" block to the sizeGB and tier input fields.
+func diskInput(m map[string]interface{}) map[string]interface{} {
+	input := map[string]interface{}{
+		"sizeGB": m["size_gb"].(int),
+	}
+	if tier, _ := m["tier_id"].(string); tier != "" {
+		input["tier"] = tier
+	}
+	return input
+}
+
+// updateVirtualHostDisks reconciles "disk" blocks by position, like updateVirtualHostInterfaces:
+// blocks present before and after are grown or moved to another tier in place, new trailing
+// blocks are attached, and removed trailing blocks are detached. Disks cannot shrink, and
+// only trailing disks can be removed (see checkTrailingRemoval).
+func updateVirtualHostDisks(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics {
+	oldRaw, newRaw := d.GetChange("disk")
+	oldList := oldRaw.([]interface{})
+	newList := newRaw.([]interface{})
+
+	if err := checkTrailingRemoval("disk", oldList, newList, diskConfigKeys); err != nil {
+		return diag.FromErr(err)
+	}
+
+	for i := 0; i < len(oldList) && i < len(newList); i++ {
+		oldDisk := oldList[i].(map[string]interface{})
+		newDisk := newList[i].(map[string]interface{})
+
+		oldSize, newSize := oldDisk["size_gb"].(int), newDisk["size_gb"].(int)
+		if newSize < oldSize {
+			return diag.Errorf("disk.%d: size_gb cannot be decreased from %d to %d; remove the disk block to detach the disk instead", i, oldSize, newSize)
+		}
+
+		input := map[string]interface{}{}
+		if newSize != oldSize {
+			input["sizeGB"] = newSize
+		}
+		if tier, _ := newDisk["tier_id"].(string); tier != "" && tier != oldDisk["tier_id"] {
+			input["tier"] = tier
+		}
+		if len(input) == 0 {
+			continue
+		}
+
+		id, _ := oldDisk["id"].(string)
+		if id == "" {
+			return diag.Errorf("disk.%d: disk ID is unknown; run `terraform refresh` and apply again", i)
+		}
+		input["disk"] = id
+		if diags := runTaskMutation(ctx, client, mutationUpdateDisk, "diskUpdate", input); diags.HasError() {
+			return diags
+		}
+	}
+
+	for i := len(oldList); i < len(newList); i++ {
+		input := diskInput(newList[i].(map[string]interface{}))
+		input["virtualHost"] = d.Id()
+		if diags := runTaskMutation(ctx, client, mutationCreateDisk, "diskCreate", input); diags.HasError() {
+			return diags
+		}
+	}
+
+	for i := len(newList); i < len(oldList); i++ {
+		id, _ := oldList[i].(map[string]interface{})["id"].(string)
+		if id == "" {
+			return diag.Errorf("disk.%d: disk ID is unknown; run `terraform refresh` and apply again", i)
+		}
+		if diags := runTaskMutation(ctx, client, mutationDeleteDisk, "diskDelete", map[string]interface{}{"disk": id}); diags.HasError() {
+			return diags
+		}
+	}
+
+	return nil
+}
+
+// flattenDisks maps API data disks to "disk" blocks. The system disk is managed by the
+// template and is not part of "disk".
+func flattenDisks(disks []apiDisk) []interface{} {
+	out := make([]interface{}, 0, len(disks))
+	for _, disk := range disks {
+		if disk.SystemDisk {
+			continue
+		}
+		out = append(out, map[string]interface{}{
+			"id":      disk.ID,
+			"size_gb": disk.SizeGB,
+			"tier_id": disk.Tier.ID,
+		})
+	}
+	return out
+}
@@ -2,12 +2,13 @@ package resources
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
 )
 
 // ResourceVirtualHostImmutable manages virtual hosts created with ignition config data.
@@ -18,8 +19,23 @@ func ResourceVirtualHostImmutable() *schema.Resource {
 		UpdateContext: ResourceVirtualHostImmutableUpdate,
 		DeleteContext: ResourceVirtualHostDelete,
 
+		// Sizing, template and tier mistakes, resizes that need a restart and replacements of
+		// protected virtual hosts are reported at plan time.
+		CustomizeDiff: customdiff.All(
+			customizeVirtualHostDiff,
+			customizeVirtualHostResizeDiff,
+			customizeDeletionProtectionDiff(ResourceVirtualHostImmutable),
+		),
+
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importVirtualHost,
+		},
+
+		// Provisioning (see wait_for_state), resize, tier changes and deletion are awaited within these timeouts.
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultVirtualHostTaskTimeout),
+			Update: schema.DefaultTimeout(defaultVirtualHostTaskTimeout),
+			Delete: schema.DefaultTimeout(defaultVirtualHostTaskTimeout),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -56,6 +72,8 @@ func ResourceVirtualHostImmutable() *schema.Resource {
 				Description: "ID of the storage tier assigned to the virtual host.",
 				Required:    true,
 			},
+			"tier_extended": tierExtendedSchema(),
+			"target_iops":   targetIOPSSchema(),
 			"cpu_count": {
 				Type:        schema.TypeInt,
 				Description: "Cpu count.",
@@ -102,7 +120,7 @@ func ResourceVirtualHostImmutable() *schema.Resource {
 			},
 			"allow_resize_restart": {
 				Type:        schema.TypeBool,
-				Description: "Allow resize restart.",
+				Description: "Allow restarting the virtual host when a resize cannot be applied while it is running. When false, such resizes fail at plan time.",
 				Optional:    true,
 				Default:     true,
 			},
@@ -177,6 +195,18 @@ func ResourceVirtualHostImmutable() *schema.Resource {
 					},
 				},
 			},
+			"power_state":         powerStateSchema(),
+			"deletion_protection": deletionProtectionSchema(),
+			"wait_for_state": {
+				Type:        schema.TypeString,
+				Description: "Status to wait for after create, e.g. `RUNNING`. When empty, create returns as soon as the API accepts the request.",
+				Optional:    true,
+			},
+			"resize_restart_required": {
+				Type:        schema.TypeBool,
+				Description: "Whether the most recently planned resize restarts the virtual host, based on its power state and CPU / memory hot-add settings.",
+				Computed:    true,
+			},
 			"uuid": {
 				Type:        schema.TypeString,
 				Description: "Uuid.",
@@ -205,27 +235,14 @@ mutation CreateVmImmutable($input: VirtualHostCreateImmutableInput!) {
         coresPerSocket
         memorySizeMB
         tier { id }
+        isExtended
+        targetIops
         template { id }
         project { id }
         customer { id }
         region
       }
-    }
-    ... on ValidationErrors {
-      message
-      errors {
-        field
-        messages
-      }
-    }
-    ... on Unauthorized {
-      message
-    }
-    ... on OperationUnavailable {
-      message
-      reasons
-    }
-  }
+    }` + payloadErrorSelection + `  }
 }
 `
 
@@ -238,17 +255,14 @@ type immutableVirtualHost struct {
 	CoresPerSocket int                 `json:"coresPerSocket"`
 	MemorySizeMB   int                 `json:"memorySizeMB"`
 	Tier           struct{ ID string } `json:"tier"`
+	IsExtended     bool                `json:"isExtended"`
+	TargetIOPS     *int                `json:"targetIops"`
 	Template       struct{ ID string } `json:"template"`
 	Project        struct{ ID string } `json:"project"`
 	Customer       struct{ ID string } `json:"customer"`
 	Region         string              `json:"region"`
 }
 
-type immutableFieldMessages struct {
-	Field    string   `json:"field"`
-	Messages []string `json:"messages"`
-}
-
 // ResourceVirtualHostImmutableCreate creates a new virtual host via the API.
 func ResourceVirtualHostImmutableCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*ocpclient.Client)
@@ -270,6 +284,7 @@ func ResourceVirtualHostImmutableCreate(ctx context.Context, d *schema.ResourceD
 		"notifyUser":                 d.Get("notify_user").(bool),
 		"clusterType":                d.Get("cluster_type").(string),
 	}
+	addTierOptions(d, input)
 
 	if v, ok := d.GetOk("data_protection_policy"); ok {
 		input["dataProtectionPolicy"] = v.(string)
@@ -333,98 +348,83 @@ func ResourceVirtualHostImmutableCreate(ctx context.Context, d *schema.ResourceD
 		"input": input,
 	}
 
-	var createResp struct {
-		VirtualHostCreateImmutable struct {
-			Typename    string                   `json:"__typename"`
-			VirtualHost *immutableVirtualHost    `json:"virtualHost,omitempty"`
-			Message     string                   `json:"message,omitempty"`
-			Errors      []immutableFieldMessages `json:"errors,omitempty"`
-			Reasons     []string                 `json:"reasons,omitempty"`
-		} `json:"virtualHostCreateImmutable"`
+	type virtualHostCreated struct {
+		VirtualHost *immutableVirtualHost `json:"virtualHost"`
 	}
 
-	if err := client.Do(mutationCreateImmutableVM, vars, &createResp); err != nil {
-		return diag.FromErr(err)
+	created, err := ocpclient.Mutate[virtualHostCreated](ctx, client, mutationCreateImmutableVM, vars, "virtualHostCreateImmutable", "VirtualHostCreated")
+	if err != nil {
+		return diagnostics.FromErr(err)
+	}
+	if created.VirtualHost == nil {
+		return diag.Errorf("virtualHostCreateImmutable: backend returned VirtualHostCreated without virtualHost")
 	}
 
-	payload := createResp.VirtualHostCreateImmutable
-
-	switch payload.Typename {
-	case "VirtualHostCreated":
-		if payload.VirtualHost == nil {
-			return diag.Errorf("virtualHostCreateImmutable: backend returned VirtualHostCreated without virtualHost")
-		}
-
-		vm := payload.VirtualHost
-		d.SetId(vm.ID)
-		_ = d.Set("uuid", vm.UUID)
-		_ = d.Set("hostname", vm.Hostname)
-		_ = d.Set("cpu_count", vm.CpuCount)
-		_ = d.Set("cores_per_socket", vm.CoresPerSocket)
-		_ = d.Set("memory_size_gb", vm.MemorySizeMB/1024)
-		_ = d.Set("status", vm.State)
-		_ = d.Set("project_id", vm.Project.ID)
-		_ = d.Set("customer_id", vm.Customer.ID)
-		_ = d.Set("tier_id", vm.Tier.ID)
-		_ = d.Set("template_id", vm.Template.ID)
-		_ = d.Set("region", vm.Region)
-		_ = d.Set("note", d.Get("note").(string))
-		if v, ok := d.GetOk("data_protection_policy"); ok {
-			_ = d.Set("data_protection_policy", v.(string))
-		}
-		_ = d.Set("ignition_config_data", d.Get("ignition_config_data").(string))
-		_ = d.Set("ignition_config_data_encoding", d.Get("ignition_config_data_encoding").(string))
-		_ = d.Set("os_disk_size_gb", d.Get("os_disk_size_gb").(int))
-		_ = d.Set("notify_user", d.Get("notify_user").(bool))
-		_ = d.Set("cluster_type", d.Get("cluster_type").(string))
-		if v, ok := d.GetOk("version"); ok {
-			_ = d.Set("version", v.(string))
-		}
-		if v, ok := d.GetOk("anti_affinity"); ok {
-			_ = d.Set("anti_affinity", v.(string))
-		}
-		if v, ok := d.GetOk("business_service"); ok {
-			_ = d.Set("business_service", v.(string))
-		}
-		if v, ok := d.GetOk("dedicated_cluster"); ok {
-			_ = d.Set("dedicated_cluster", v.(string))
-		}
-		if v, ok := d.GetOk("dedicated_dr_cluster"); ok {
-			_ = d.Set("dedicated_dr_cluster", v.(string))
-		}
-		if v, ok := d.GetOk("interfaces"); ok {
-			_ = d.Set("interfaces", v)
-		}
-		if v, ok := d.GetOk("local_disk_list"); ok {
-			_ = d.Set("local_disk_list", v)
-		}
-
-		return nil
+	vm := created.VirtualHost
+	d.SetId(vm.ID)
+	_ = d.Set("uuid", vm.UUID)
+	_ = d.Set("hostname", vm.Hostname)
+	_ = d.Set("cpu_count", vm.CpuCount)
+	_ = d.Set("cores_per_socket", vm.CoresPerSocket)
+	_ = d.Set("memory_size_gb", vm.MemorySizeMB/1024)
+	_ = d.Set("status", vm.State)
+	_ = d.Set("project_id", vm.Project.ID)
+	_ = d.Set("customer_id", vm.Customer.ID)
+	_ = d.Set("tier_id", vm.Tier.ID)
+	_ = d.Set("tier_extended", vm.IsExtended)
+	if vm.TargetIOPS != nil {
+		_ = d.Set("target_iops", *vm.TargetIOPS)
+	}
+	_ = d.Set("template_id", vm.Template.ID)
+	_ = d.Set("region", vm.Region)
+	_ = d.Set("note", d.Get("note").(string))
+	if v, ok := d.GetOk("data_protection_policy"); ok {
+		_ = d.Set("data_protection_policy", v.(string))
+	}
+	_ = d.Set("ignition_config_data", d.Get("ignition_config_data").(string))
+	_ = d.Set("ignition_config_data_encoding", d.Get("ignition_config_data_encoding").(string))
+	_ = d.Set("os_disk_size_gb", d.Get("os_disk_size_gb").(int))
+	_ = d.Set("notify_user", d.Get("notify_user").(bool))
+	_ = d.Set("cluster_type", d.Get("cluster_type").(string))
+	if v, ok := d.GetOk("version"); ok {
+		_ = d.Set("version", v.(string))
+	}
+	if v, ok := d.GetOk("anti_affinity"); ok {
+		_ = d.Set("anti_affinity", v.(string))
+	}
+	if v, ok := d.GetOk("business_service"); ok {
+		_ = d.Set("business_service", v.(string))
+	}
+	if v, ok := d.GetOk("dedicated_cluster"); ok {
+		_ = d.Set("dedicated_cluster", v.(string))
+	}
+	if v, ok := d.GetOk("dedicated_dr_cluster"); ok {
+		_ = d.Set("dedicated_dr_cluster", v.(string))
+	}
+	if v, ok := d.GetOk("interfaces"); ok {
+		_ = d.Set("interfaces", v)
+	}
+	if v, ok := d.GetOk("local_disk_list"); ok {
+		_ = d.Set("local_disk_list", v)
+	}
 
-	case "ValidationErrors":
-		msg := payload.Message
-		if len(payload.Errors) > 0 {
-			var details string
-			for _, e := range payload.Errors {
-				details += fmt.Sprintf("%s: %v; ", e.Field, e.Messages)
-			}
-			msg = fmt.Sprintf("%s (%s)", msg, details)
+	// The ID is already set, so a failed wait leaves the resource tainted.
+	if target := d.Get("wait_for_state").(string); target != "" {
+		state, diags := waitForVirtualHostState(ctx, client, vm.ID, target)
+		_ = d.Set("status", state)
+		if diags.HasError() {
+			return diags
 		}
-		if msg == "" {
-			msg = "validation failed without message"
-		}
-		return diag.Errorf("virtualHostCreateImmutable: %s", msg)
+	}
 
-	case "Unauthorized", "OperationUnavailable":
-		msg := payload.Message
-		if msg == "" {
-			msg = fmt.Sprintf("%s", payload.Typename)
+	// New virtual hosts start powered on.
+	if state, ok := d.GetOk("power_state"); ok && state.(string) != powerStateOn {
+		if diags := setVirtualHostPowerState(ctx, client, vm.ID, state.(string)); diags.HasError() {
+			return diags
 		}
-		return diag.Errorf("virtualHostCreateImmutable: %s", msg)
-
-	default:
-		return diag.Errorf("virtualHostCreateImmutable: unexpected payload type %q", payload.Typename)
 	}
+
+	return nil
 }
 
 // ResourceVirtualHostImmutableRead refreshes Terraform state from the API.
@@ -439,6 +439,7 @@ func ResourceVirtualHostImmutableRead(ctx context.Context, d *schema.ResourceDat
 			UUID                 string `json:"uuid"`
 			Hostname             string `json:"hostname"`
 			State                string `json:"state"`
+			PowerState           string `json:"powerState"`
 			CpuCount             int    `json:"cpuCount"`
 			CoresPerSocket       int    `json:"coresPerSocket"`
 			MemorySizeMB         int    `json:"memorySizeMB"`
@@ -452,16 +453,23 @@ func ResourceVirtualHostImmutableRead(ctx context.Context, d *schema.ResourceDat
 					IP string `json:"ip"`
 				} `json:"ipv4Addresses"`
 			} `json:"networkInterfaceList"`
-			Tier     struct{ ID string } `json:"tier"`
-			Template struct{ ID string } `json:"template"`
-			Project  struct{ ID string } `json:"project"`
-			Customer struct{ ID string } `json:"customer"`
-			Region   string              `json:"region"`
+			Tier       struct{ ID string } `json:"tier"`
+			IsExtended bool                `json:"isExtended"`
+			TargetIOPS *int                `json:"targetIops"`
+			Template   struct{ ID string } `json:"template"`
+			Project    struct{ ID string } `json:"project"`
+			Customer   struct{ ID string } `json:"customer"`
+			Region     string              `json:"region"`
 		} `json:"virtualHost"`
 	}
 
-	if err := client.Do(queryGetVM, vars, &resp); err != nil {
-		return diag.FromErr(err)
+	if err := client.DoContext(ctx, queryGetVM, vars, &resp); err != nil {
+		// The VM was deleted outside Terraform; drop it from state so it gets recreated.
+		if ocpclient.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diagnostics.FromErr(err)
 	}
 
 	if resp.VirtualHost == nil {
@@ -474,6 +482,7 @@ func ResourceVirtualHostImmutableRead(ctx context.Context, d *schema.ResourceDat
 	_ = d.Set("uuid", vh.UUID)
 	_ = d.Set("hostname", vh.Hostname)
 	_ = d.Set("status", vh.State)
+	_ = d.Set("power_state", flattenPowerState(vh.PowerState))
 	_ = d.Set("cpu_count", vh.CpuCount)
 	_ = d.Set("cores_per_socket", vh.CoresPerSocket)
 	_ = d.Set("memory_size_gb", vh.MemorySizeMB/1024)
@@ -482,12 +491,15 @@ func ResourceVirtualHostImmutableRead(ctx context.Context, d *schema.ResourceDat
 		_ = d.Set("data_protection_policy", vh.DataProtectionPolicy.ID)
 	}
 	_ = d.Set("tier_id", vh.Tier.ID)
+	_ = d.Set("tier_extended", vh.IsExtended)
+	_ = d.Set("target_iops", flattenTargetIOPS(vh.TargetIOPS))
 	_ = d.Set("template_id", vh.Template.ID)
 	_ = d.Set("project_id", vh.Project.ID)
 	_ = d.Set("customer_id", vh.Customer.ID)
 	_ = d.Set("region", vh.Region)
 
 	_ = d.Set("allow_resize_restart", d.Get("allow_resize_restart").(bool))
+	_ = d.Set("deletion_protection", d.Get("deletion_protection").(bool))
 	_ = d.Set("ignition_config_data", d.Get("ignition_config_data").(string))
 	_ = d.Set("ignition_config_data_encoding", d.Get("ignition_config_data_encoding").(string))
 	_ = d.Set("os_disk_size_gb", d.Get("os_disk_size_gb").(int))
@@ -533,145 +545,14 @@ func ResourceVirtualHostImmutableRead(ctx context.Context, d *schema.ResourceDat
 	return nil
 }
 
-// ResourceVirtualHostImmutableUpdate updates either sizing or tier; applying both in one run is rejected.
+// ResourceVirtualHostImmutableUpdate applies sizing changes first, then the tier change, then the power state.
 func ResourceVirtualHostImmutableUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*ocpclient.Client)
 
-	sizingChanged := d.HasChange("cpu_count") || d.HasChange("cores_per_socket") || d.HasChange("memory_size_gb")
-	tierChanged := d.HasChange("tier_id")
-
-	changeGroups := 0
-	if sizingChanged {
-		changeGroups++
-	}
-	if tierChanged {
-		changeGroups++
-	}
-
-	if changeGroups == 0 {
-		return ResourceVirtualHostImmutableRead(ctx, d, meta)
-	}
-
-	if changeGroups > 1 {
-		return diag.Errorf(
-			"ocp_virtual_host_immutable: simultaneous change of sizing (cpu_count/cores_per_socket/memory_size_gb) and tier_id in a single apply is not supported. " +
-				"Please apply sizing changes first, wait for the job to finish, and then apply tier change in a separate terraform apply.",
-		)
-	}
-
-	if sizingChanged {
-		input := map[string]interface{}{
-			"virtualHost":  d.Id(),
-			"allowRestart": d.Get("allow_resize_restart").(bool),
-		}
-
-		if d.HasChange("cpu_count") {
-			input["cpuCount"] = d.Get("cpu_count").(int)
-		}
-		if d.HasChange("cores_per_socket") {
-			input["coresPerSocket"] = d.Get("cores_per_socket").(int)
-		}
-		if d.HasChange("memory_size_gb") {
-			input["memorySizeGB"] = d.Get("memory_size_gb").(int)
-		}
-
-		var respResize struct {
-			VirtualHostResize struct {
-				Typename string          `json:"__typename"`
-				Message  string          `json:"message,omitempty"`
-				Errors   []gqlFieldError `json:"errors,omitempty"`
-				Reasons  []string        `json:"reasons,omitempty"`
-			} `json:"virtualHostResize"`
-		}
-
-		if err := client.Do(mutationResizeVm, map[string]interface{}{
-			"input": input,
-		}, &respResize); err != nil {
-			return diag.FromErr(err)
-		}
-
-		p := respResize.VirtualHostResize
-
-		switch p.Typename {
-		case "TaskExecutionNode":
-			// OK: job started.
-
-		case "ValidationErrors":
-			msg := p.Message
-			for _, e := range p.Errors {
-				msg += fmt.Sprintf(" %s: %v;", e.Field, e.Messages)
-			}
-			if msg == "" {
-				msg = "validation failed without message"
-			}
-			return diag.Errorf("virtualHostResize: %s", msg)
-
-		case "Unauthorized", "OperationUnavailable":
-			msg := p.Message
-			if len(p.Reasons) > 0 {
-				msg = fmt.Sprintf("%s (reasons=%v)", msg, p.Reasons)
-			}
-			if msg == "" {
-				msg = p.Typename
-			}
-			return diag.Errorf("virtualHostResize: %s", msg)
-
-		default:
-			return diag.Errorf("virtualHostResize: unexpected payload type %q", p.Typename)
-		}
-	}
-
-	if tierChanged {
-		input := map[string]interface{}{
-			"virtualHost": d.Id(),
-			"tier":        d.Get("tier_id").(string),
-		}
-
-		var respTier struct {
-			VirtualHostUpdateTier struct {
-				Typename string          `json:"__typename"`
-				Message  string          `json:"message,omitempty"`
-				Errors   []gqlFieldError `json:"errors,omitempty"`
-				Reasons  []string        `json:"reasons,omitempty"`
-			} `json:"virtualHostUpdateTier"`
-		}
-
-		if err := client.Do(mutationUpdateVmTier, map[string]interface{}{
-			"input": input,
-		}, &respTier); err != nil {
-			return diag.FromErr(err)
-		}
-
-		p := respTier.VirtualHostUpdateTier
-
-		switch p.Typename {
-		case "TaskExecutionNode":
-			// OK: job started.
-
-		case "ValidationErrors":
-			msg := p.Message
-			for _, e := range p.Errors {
-				msg += fmt.Sprintf(" %s: %v;", e.Field, e.Messages)
-			}
-			if msg == "" {
-				msg = "validation failed without message"
-			}
-			return diag.Errorf("virtualHostUpdateTier: %s", msg)
-
-		case "Unauthorized", "OperationUnavailable":
-			msg := p.Message
-			if len(p.Reasons) > 0 {
-				msg = fmt.Sprintf("%s (reasons=%v)", msg, p.Reasons)
-			}
-			if msg == "" {
-				msg = p.Typename
-			}
-			return diag.Errorf("virtualHostUpdateTier: %s", msg)
-
-		default:
-			return diag.Errorf("virtualHostUpdateTier: unexpected payload type %q", p.Typename)
-		}
+	diags := applyUpdateSteps(ctx, d, client, virtualHostSizingStep, virtualHostTierStep, virtualHostPowerStep)
+	if diags.HasError() {
+		return diags
 	}
 
-	return ResourceVirtualHostImmutableRead(ctx, d, meta)
+	return append(diags, ResourceVirtualHostImmutableRead(ctx, d, meta)...)
 }
@@ -0,0 +1,123 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+const queryVirtualHostImportLookup = `
+query VirtualHostImportLookup($hostname: StrFilterLookup, $uuid: StrFilterLookup, $project: ProjectFilter, $first: Int, $after: String) {
+  virtualHostList(filters: { hostname: $hostname, uuid: $uuid, project: $project }, first: $first, after: $after) {
+    edges {
+      node {
+        id
+        hostname
+        uuid
+        project {
+          id
+          name
+        }
+      }
+    }
+    pageInfo {
+      hasNextPage
+      endCursor
+    }
+  }
+}
+`
+
+// importVirtualHost is the import function of all virtual host resources.
+//
+// The import ID is either the VirtualHost GlobalID, or a lookup of the form
+// "hostname=<name>[,project=<name>]" or "uuid=<uuid>" resolved through virtualHostList.
+// A lookup must match exactly one virtual host.
+func importVirtualHost(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	importID := d.Id()
+	// GlobalIDs are base64 and may only carry "=" as trailing padding.
+	if !strings.Contains(strings.TrimRight(importID, "="), "=") {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	lookup, err := parseVirtualHostImportID(importID)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]interface{}{}
+	if v := lookup["hostname"]; v != "" {
+		vars["hostname"] = map[string]interface{}{"exact": v}
+	}
+	if v := lookup["uuid"]; v != "" {
+		vars["uuid"] = map[string]interface{}{"exact": v}
+	}
+	if v := lookup["project"]; v != "" {
+		vars["project"] = map[string]interface{}{
+			"name": map[string]interface{}{"exact": v},
+		}
+	}
+
+	type virtualHostNode struct {
+		ID       string `json:"id"`
+		Hostname string `json:"hostname"`
+		UUID     string `json:"uuid"`
+		Project  struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"project"`
+	}
+
+	client := meta.(*ocpclient.Client)
+	nodes, err := ocpclient.Paginate[virtualHostNode](ctx, client, queryVirtualHostImportLookup, vars, "virtualHostList")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up virtual host %q: %w", importID, err)
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil, fmt.Errorf("no virtual host found for %q", importID)
+	case 1:
+		d.SetId(nodes[0].ID)
+		return []*schema.ResourceData{d}, nil
+	default:
+		matches := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			matches = append(matches, fmt.Sprintf("%s (project %q, uuid %s)", n.Hostname, n.Project.Name, n.UUID))
+		}
+		return nil, fmt.Errorf("%q matches %d virtual hosts: %s; add project=<name> or import by uuid=<uuid>",
+			importID, len(nodes), strings.Join(matches, ", "))
+	}
+}
+
+// parseVirtualHostImportID parses "key=value" pairs separated by commas.
+func parseVirtualHostImportID(importID string) (map[string]string, error) {
+	const usage = `expected "hostname=<name>[,project=<name>]", "uuid=<uuid>" or a VirtualHost GlobalID`
+
+	lookup := map[string]string{}
+	for _, pair := range strings.Split(importID, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid import ID %q: %s", importID, usage)
+		}
+		switch key {
+		case "hostname", "project", "uuid":
+		default:
+			return nil, fmt.Errorf("invalid import ID %q: unknown key %q; %s", importID, key, usage)
+		}
+		if _, dup := lookup[key]; dup {
+			return nil, fmt.Errorf("invalid import ID %q: %q given more than once", importID, key)
+		}
+		lookup[key] = value
+	}
+
+	if lookup["hostname"] == "" && lookup["uuid"] == "" {
+		return nil, fmt.Errorf("invalid import ID %q: %s", importID, usage)
+	}
+	return lookup, nil
+}
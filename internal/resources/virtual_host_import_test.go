@@ -0,0 +1,160 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+func TestParseVirtualHostImportID(t *testing.T) {
+	testCases := []struct {
+		importID string
+		want     map[string]string
+		wantErr  string
+	}{
+		{importID: "hostname=app-1", want: map[string]string{"hostname": "app-1"}},
+		{importID: "hostname=app-1, project=project-a", want: map[string]string{"hostname": "app-1", "project": "project-a"}},
+		{importID: "uuid=4210a1b2-0000-4000-8000-000000000001", want: map[string]string{"uuid": "4210a1b2-0000-4000-8000-000000000001"}},
+		{importID: "project=project-a", wantErr: "expected"},
+		{importID: "name=app-1", wantErr: `unknown key "name"`},
+		{importID: "hostname=", wantErr: "expected"},
+		{importID: "hostname=a,hostname=b", wantErr: "more than once"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.importID, func(t *testing.T) {
+			got, err := parseVirtualHostImportID(tc.importID)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+			for k, v := range tc.want {
+				if got[k] != v {
+					t.Fatalf("expected %v, got %v", tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestImportVirtualHost(t *testing.T) {
+	node := func(id, project string) map[string]interface{} {
+		return map[string]interface{}{
+			"node": map[string]interface{}{
+				"id":       id,
+				"hostname": "app-1",
+				"uuid":     "uuid-" + id,
+				"project":  map[string]interface{}{"id": "p-" + project, "name": project},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		importID string
+		edges    []interface{}
+		wantID   string
+		wantErr  string
+		wantVars map[string]interface{}
+	}{
+		{
+			name:     "global id",
+			importID: "VmlydHVhbEhvc3ROb2RlOjE=",
+			wantID:   "VmlydHVhbEhvc3ROb2RlOjE=",
+		},
+		{
+			name:     "hostname and project",
+			importID: "hostname=app-1,project=project-a",
+			edges:    []interface{}{node("vh-1", "project-a")},
+			wantID:   "vh-1",
+			wantVars: map[string]interface{}{
+				"hostname": map[string]interface{}{"exact": "app-1"},
+				"project":  map[string]interface{}{"name": map[string]interface{}{"exact": "project-a"}},
+			},
+		},
+		{
+			name:     "ambiguous hostname",
+			importID: "hostname=app-1",
+			edges:    []interface{}{node("vh-1", "project-a"), node("vh-2", "project-b")},
+			wantErr:  "matches 2 virtual hosts",
+		},
+		{
+			name:     "no match",
+			importID: "uuid=missing",
+			edges:    []interface{}{},
+			wantErr:  "no virtual host found",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query     string                 `json:"query"`
+					Variables map[string]interface{} `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request: %v", err)
+				}
+				if tc.edges == nil {
+					t.Fatalf("unexpected lookup for %q", tc.importID)
+				}
+				for k, want := range tc.wantVars {
+					got, _ := json.Marshal(body.Variables[k])
+					wantJSON, _ := json.Marshal(want)
+					if string(got) != string(wantJSON) {
+						t.Fatalf("variable %s: expected %s, got %s", k, wantJSON, got)
+					}
+				}
+
+				response := map[string]interface{}{
+					"data": map[string]interface{}{
+						"virtualHostList": map[string]interface{}{
+							"edges":    tc.edges,
+							"pageInfo": map[string]interface{}{"hasNextPage": false},
+						},
+					},
+				}
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Fatalf("encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := ocpclient.New(server.URL, "token", true)
+			data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, map[string]interface{}{})
+			data.SetId(tc.importID)
+
+			result, err := importVirtualHost(context.Background(), data, client)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result) != 1 || result[0].Id() != tc.wantID {
+				t.Fatalf("expected id %q, got %q", tc.wantID, result[0].Id())
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+const mutationCreateNetworkInterface = `
+mutation CreateNetworkInterface($input: NetworkInterfaceCreateInput!) {
+  networkInterfaceCreate(input: $input) {
+    __typename
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
+
+const mutationUpdateNetworkInterface = `
+mutation UpdateNetworkInterface($input: NetworkInterfaceUpdateInput!) {
+  networkInterfaceUpdate(input: $input) {
+    __typename
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
+
+const mutationDeleteNetworkInterface = `
+mutation DeleteNetworkInterface($input: NetworkInterfaceDeleteInput!) {
+  networkInterfaceDelete(input: $input) {
+    __typename
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
+
+// apiIPAddress is an address assigned to a NetworkInterface.
+type apiIPAddress struct {
+	IP        string `json:"ip"`
+	Prefixlen int    `json:"prefixlen"`
+}
+
+// apiNetworkInterface is a NetworkInterface as returned by the virtualHost query.
+type apiNetworkInterface struct {
+	ID             string              `json:"id"`
+	Label          string              `json:"label"`
+	MACAddress     string              `json:"macAddress"`
+	Network        struct{ ID string } `json:"network"`
+	IPv4Addresses  []apiIPAddress      `json:"ipv4Addresses"`
+	IPv6Addresses  []apiIPAddress      `json:"ipv6Addresses"`
+	StartConnected bool                `json:"startConnected"`
+}
+
+// ipAddressSchema describes the read-only ipv4_addresses / ipv6_addresses entries.
+func ipAddressSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"address": {
+					Type:        schema.TypeString,
+					Description: "IP address.",
+					Computed:    true,
+				},
+				"prefix_length": {
+					Type:        schema.TypeInt,
+					Description: "Prefix length of the subnet.",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// flattenIPAddresses maps API addresses to ipv4_addresses / ipv6_addresses entries.
+func flattenIPAddresses(addrs []apiIPAddress) []interface{} {
+	out := make([]interface{}, 0, len(addrs))
+	for _, a := range addrs {
+		out = append(out, map[string]interface{}{
+			"address":       a.IP,
+			"prefix_length": a.Prefixlen,
+		})
+	}
+	return out
+}
+
+// primaryIP returns the first IPv4 address of the first interface that has one, falling back
+// to the first IPv6 address, or an empty string when no address is assigned.
+func primaryIP(nics []apiNetworkInterface) string {
+	for _, ni := range nics {
+		if len(ni.IPv4Addresses) > 0 {
+			return ni.IPv4Addresses[0].IP
+		}
+	}
+	for _, ni := range nics {
+		if len(ni.IPv6Addresses) > 0 {
+			return ni.IPv6Addresses[0].IP
+		}
+	}
+	return ""
+}
+
+// virtualHostInterfacesStep adds, changes and removes network interfaces of ocp_virtual_host.
+var virtualHostInterfacesStep = virtualHostUpdateStep{
+	keys:  []string{"interfaces"},
+	apply: updateVirtualHostInterfaces,
+}
+
+// interfaceInput maps an "interfaces" block to the network, autoAssignIp and ipList input fields.
+func interfaceInput(m map[string]interface{}) map[string]interface{} {
+	input := map[string]interface{}{
+		"network": m["network_id"].(string),
+	}
+
+	// autoAssignIp
+	if v, ok := m["auto_assign_ip"]; ok {
+		input["autoAssignIp"] = v.(bool)
+	}
+
+	// ip -> ipList (single element)
+	if ipRaw, ok := m["ip"]; ok {
+		ip := ipRaw.(string)
+		if ip != "" {
+			input["ipList"] = []string{ip}
+		}
+	}
+
+	return input
+}
+
+// interfaceConfigKeys are the configurable attributes of an "interfaces" block.
+var interfaceConfigKeys = []string{"network_id", "auto_assign_ip", "ip"}
+
+// interfaceChanged reports whether the configurable attributes of two "interfaces" blocks differ.
+func interfaceChanged(old, new map[string]interface{}) bool {
+	for _, key := range interfaceConfigKeys {
+		if old[key] != new[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// updateVirtualHostInterfaces reconciles "interfaces" by position: new trailing blocks are
+// attached, blocks present in both the prior state and the plan are updated in place, and removed
+// trailing blocks are detached; only trailing interfaces can be removed (see checkTrailingRemoval).
+// New interfaces are attached before any interface is moved to another network or detached, so
+// the virtual host keeps its connectivity while networks are swapped.
+func updateVirtualHostInterfaces(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics {
+	oldRaw, newRaw := d.GetChange("interfaces")
+	oldList := oldRaw.([]interface{})
+	newList := newRaw.([]interface{})
+
+	if err := checkTrailingRemoval("interfaces", oldList, newList, interfaceConfigKeys); err != nil {
+		return diag.FromErr(err)
+	}
+
+	for i := len(oldList); i < len(newList); i++ {
+		input := interfaceInput(newList[i].(map[string]interface{}))
+		input["virtualHost"] = d.Id()
+		if diags := runTaskMutation(ctx, client, mutationCreateNetworkInterface, "networkInterfaceCreate", input); diags.HasError() {
+			return diags
+		}
+	}
+
+	for i := 0; i < len(oldList) && i < len(newList); i++ {
+		oldIface := oldList[i].(map[string]interface{})
+		newIface := newList[i].(map[string]interface{})
+		if !interfaceChanged(oldIface, newIface) {
+			continue
+		}
+
+		id, diags := interfaceID(oldIface, i)
+		if diags.HasError() {
+			return diags
+		}
+
+		input := interfaceInput(newIface)
+		input["networkInterface"] = id
+		if diags := runTaskMutation(ctx, client, mutationUpdateNetworkInterface, "networkInterfaceUpdate", input); diags.HasError() {
+			return diags
+		}
+	}
+
+	for i := len(newList); i < len(oldList); i++ {
+		id, diags := interfaceID(oldList[i].(map[string]interface{}), i)
+		if diags.HasError() {
+			return diags
+		}
+		input := map[string]interface{}{"networkInterface": id}
+		if diags := runTaskMutation(ctx, client, mutationDeleteNetworkInterface, "networkInterfaceDelete", input); diags.HasError() {
+			return diags
+		}
+	}
+
+	return nil
+}
+
+// interfaceID returns the API ID of the interface recorded in state at index i.
+func interfaceID(m map[string]interface{}, i int) (string, diag.Diagnostics) {
+	id, _ := m["id"].(string)
+	if id == "" {
+		return "", diag.Errorf("interfaces.%d: network interface ID is unknown; run `terraform refresh` and apply again", i)
+	}
+	return id, nil
+}
+
+// flattenInterfaces maps API network interfaces to "interfaces" blocks.
+//
+// The API doesn't say whether an address was assigned automatically, so auto_assign_ip is taken
+// from the block at the same position in current state/config. Where there is none (typical
+// during import), the interface is read as auto-assigned, the schema default: a plain
+// interfaces block then plans no change, whereas reading it as static would plan an update
+// with autoAssignIp that could re-address the running virtual host. A configured static ip
+// that matches the current address only plans an update to that same address.
+// Automatically assigned addresses are not written to "ip" to avoid spurious diffs; they stay
+// visible in ipv4_addresses.
+func flattenInterfaces(current []interface{}, nics []apiNetworkInterface) []interface{} {
+	ifaces := make([]interface{}, 0, len(nics))
+	for i, ni := range nics {
+		autoAssign := true
+		if i < len(current) {
+			if prior, ok := current[i].(map[string]interface{}); ok {
+				autoAssign, _ = prior["auto_assign_ip"].(bool)
+			}
+		}
+
+		m := map[string]interface{}{
+			"id":              ni.ID,
+			"label":           ni.Label,
+			"mac_address":     ni.MACAddress,
+			"network_id":      ni.Network.ID,
+			"auto_assign_ip":  autoAssign,
+			"ip":              "",
+			"ipv4_addresses":  flattenIPAddresses(ni.IPv4Addresses),
+			"ipv6_addresses":  flattenIPAddresses(ni.IPv6Addresses),
+			"start_connected": ni.StartConnected,
+		}
+		if !autoAssign && len(ni.IPv4Addresses) > 0 {
+			m["ip"] = ni.IPv4Addresses[0].IP
+		}
+		ifaces = append(ifaces, m)
+	}
+	return ifaces
+}
@@ -0,0 +1,92 @@
+package resources
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+	"github.com/davidhrbac/terraform-provider-ocp/internal/diagnostics"
+)
+
+// queryGetVMPlacement is kept apart from queryGetVM so that a placement field the API does not
+// expose in this shape cannot break refresh, provisioning waits or deletion.
+const queryGetVMPlacement = `
+query GetVmPlacement($id: GlobalID!) {
+  virtualHost(id: $id) {
+    id
+    antiAffinity
+    businessService
+    dedicatedCluster { id }
+    dedicatedDrCluster { id }
+    clusterType
+    version
+  }
+}
+`
+
+// apiPlacement holds the placement options of a VirtualHostNode.
+type apiPlacement struct {
+	AntiAffinity       string              `json:"antiAffinity"`
+	BusinessService    string              `json:"businessService"`
+	DedicatedCluster   struct{ ID string } `json:"dedicatedCluster"`
+	DedicatedDRCluster struct{ ID string } `json:"dedicatedDrCluster"`
+	ClusterType        string              `json:"clusterType"`
+	Version            string              `json:"version"`
+}
+
+// placementInputFields maps placement attributes of ocp_virtual_host to VirtualHostCreateInput fields.
+var placementInputFields = map[string]string{
+	"anti_affinity":        "antiAffinity",
+	"business_service":     "businessService",
+	"dedicated_cluster":    "dedicatedCluster",
+	"dedicated_dr_cluster": "dedicatedDrCluster",
+	"cluster_type":         "clusterType",
+	"version":              "version",
+}
+
+// addPlacementOptions adds the configured placement options to a create input.
+// Options that are not set are omitted so that the API applies its defaults.
+func addPlacementOptions(d *schema.ResourceData, input map[string]interface{}) {
+	for key, field := range placementInputFields {
+		if v, ok := d.GetOk(key); ok {
+			input[field] = v.(string)
+		}
+	}
+}
+
+// readPlacement reads the placement options back into state. When the API rejects the query,
+// the values in state are kept and a warning is logged.
+func readPlacement(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics {
+	var resp struct {
+		VirtualHost *apiPlacement `json:"virtualHost"`
+	}
+	if err := client.DoContext(ctx, queryGetVMPlacement, map[string]interface{}{"id": d.Id()}, &resp); err != nil {
+		var gqlErr *ocpclient.GraphQLError
+		if errors.As(err, &gqlErr) {
+			tflog.Warn(ctx, "keeping placement options from state, the API rejected the placement query", map[string]interface{}{
+				"virtual_host": d.Id(),
+				"error":        err.Error(),
+			})
+			return nil
+		}
+		return diagnostics.FromErr(err)
+	}
+	if resp.VirtualHost != nil {
+		setPlacement(d, *resp.VirtualHost)
+	}
+	return nil
+}
+
+// setPlacement reads the placement options back into state.
+func setPlacement(d *schema.ResourceData, p apiPlacement) {
+	_ = d.Set("anti_affinity", p.AntiAffinity)
+	_ = d.Set("business_service", p.BusinessService)
+	_ = d.Set("dedicated_cluster", p.DedicatedCluster.ID)
+	_ = d.Set("dedicated_dr_cluster", p.DedicatedDRCluster.ID)
+	_ = d.Set("cluster_type", p.ClusterType)
+	_ = d.Set("version", p.Version)
+}
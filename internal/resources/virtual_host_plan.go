@@ -0,0 +1,388 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+const queryTemplateConstraints = `
+query TemplateConstraints($id: GlobalID!) {
+  template(id: $id) {
+    id
+    name
+    solutionType
+    minCpuCount
+    maxCpuCount
+    maxCoresPerSocket
+    minMemorySizeGB
+    maxMemorySizeGB
+  }
+}
+`
+
+const queryTierConstraints = `
+query TierConstraints($id: GlobalID!) {
+  tier(id: $id) {
+    id
+    name
+    solutionType
+    supportsExtended
+    minIops
+    maxIops
+  }
+}
+`
+
+// templateConstraints are the sizing limits of a template. Nil limits are not enforced.
+type templateConstraints struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	SolutionType      string `json:"solutionType"`
+	MinCPUCount       *int   `json:"minCpuCount"`
+	MaxCPUCount       *int   `json:"maxCpuCount"`
+	MaxCoresPerSocket *int   `json:"maxCoresPerSocket"`
+	MinMemorySizeGB   *int   `json:"minMemorySizeGB"`
+	MaxMemorySizeGB   *int   `json:"maxMemorySizeGB"`
+}
+
+// tierConstraints are the solution type and extended options of a tier. Nil limits are not enforced.
+type tierConstraints struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	SolutionType     string `json:"solutionType"`
+	SupportsExtended bool   `json:"supportsExtended"`
+	MinIOPS          *int   `json:"minIops"`
+	MaxIOPS          *int   `json:"maxIops"`
+}
+
+// virtualHostConstraintKeys are the attributes whose changes are checked against the API.
+var virtualHostConstraintKeys = []string{
+	"cpu_count", "cores_per_socket", "memory_size_gb", "template_id", "tier_id", "tier_extended", "target_iops",
+}
+
+// customizeVirtualHostDiff rejects plans that the API would refuse at apply time.
+//
+// The CPU topology and tier options are always checked locally. Template and tier constraints
+// are looked up only when a virtual host is created or its sizing, template, tier or tier options
+// change, so plans without such changes do not query the API. When the API rejects a lookup
+// query as invalid, the corresponding checks are skipped and left to the apply.
+func customizeVirtualHostDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	errs := validateVirtualHostSizing(d)
+	errs = append(errs, validateTierOptions(d)...)
+
+	if d.Id() == "" || d.HasChanges(virtualHostConstraintKeys...) {
+		client := meta.(*ocpclient.Client)
+		apiErrs, err := validateVirtualHostConstraints(ctx, d, client)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, apiErrs...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// knownInt returns the planned value of an integer attribute, or false while it is unknown.
+func knownInt(d *schema.ResourceDiff, key string) (int, bool) {
+	if !d.NewValueKnown(key) {
+		return 0, false
+	}
+	return d.Get(key).(int), true
+}
+
+// knownString returns the planned value of a string attribute, or false while it is unknown or empty.
+func knownString(d *schema.ResourceDiff, key string) (string, bool) {
+	if !d.NewValueKnown(key) {
+		return "", false
+	}
+	v := d.Get(key).(string)
+	return v, v != ""
+}
+
+func validateVirtualHostSizing(d *schema.ResourceDiff) []error {
+	var errs []error
+
+	cpus, cpusKnown := knownInt(d, "cpu_count")
+	cores, coresKnown := knownInt(d, "cores_per_socket")
+	if cpusKnown && cpus < 1 {
+		errs = append(errs, fmt.Errorf("cpu_count must be at least 1, got %d", cpus))
+	}
+	if coresKnown && cores < 1 {
+		errs = append(errs, fmt.Errorf("cores_per_socket must be at least 1, got %d", cores))
+	}
+	if cpusKnown && coresKnown && cpus > 0 && cores > 0 && cpus%cores != 0 {
+		errs = append(errs, fmt.Errorf("cpu_count (%d) must be a multiple of cores_per_socket (%d)", cpus, cores))
+	}
+
+	if memory, ok := knownInt(d, "memory_size_gb"); ok && memory < 1 {
+		errs = append(errs, fmt.Errorf("memory_size_gb must be at least 1, got %d", memory))
+	}
+
+	return errs
+}
+
+// customizeVirtualHostDisksDiff rejects "disk" blocks that would shrink and removals of
+// disks other than the last ones, which updateVirtualHostDisks refuses at apply time.
+func customizeVirtualHostDisksDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("disk") {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange("disk")
+	oldList, _ := oldRaw.([]interface{})
+	newList, _ := newRaw.([]interface{})
+
+	var errs []error
+	for i := 0; i < len(oldList) && i < len(newList); i++ {
+		if !d.NewValueKnown(fmt.Sprintf("disk.%d.size_gb", i)) {
+			continue
+		}
+		oldSize, _ := oldList[i].(map[string]interface{})["size_gb"].(int)
+		newSize, _ := newList[i].(map[string]interface{})["size_gb"].(int)
+		if newSize < oldSize {
+			errs = append(errs, fmt.Errorf("disk.%d: size_gb cannot be decreased from %d to %d; remove the disk block to detach the disk instead", i, oldSize, newSize))
+		}
+	}
+	if err := checkTrailingRemoval("disk", oldList, newList, diskConfigKeys); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// customizeVirtualHostInterfacesDiff rejects removals of interfaces other than the last ones,
+// which updateVirtualHostInterfaces refuses at apply time.
+func customizeVirtualHostInterfacesDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("interfaces") {
+		return nil
+	}
+
+	oldRaw, newRaw := d.GetChange("interfaces")
+	oldList, _ := oldRaw.([]interface{})
+	newList, _ := newRaw.([]interface{})
+	return checkTrailingRemoval("interfaces", oldList, newList, interfaceConfigKeys)
+}
+
+// checkTrailingRemoval rejects shrinking a block list when a block that stays in the list
+// differs from the prior block at its position in one of keys. Blocks are reconciled by
+// position, so such a plan means an earlier block was removed and applying it would detach
+// the wrong device.
+func checkTrailingRemoval(name string, oldList, newList []interface{}, keys []string) error {
+	if len(newList) >= len(oldList) {
+		return nil
+	}
+	for i := range newList {
+		oldBlock, _ := oldList[i].(map[string]interface{})
+		newBlock, _ := newList[i].(map[string]interface{})
+		for _, key := range keys {
+			if oldBlock[key] != newBlock[key] {
+				return fmt.Errorf("%s.%d: %s blocks are matched by position, so only the last blocks can be removed "+
+					"and the remaining ones must not change in the same apply; remove blocks from the end of the list "+
+					"or apply the other changes separately", name, i, name)
+			}
+		}
+	}
+	return nil
+}
+
+// skipFailedLookup reports whether a failed constraint lookup should skip the check instead of
+// failing the plan. Only queries the API rejects outright, for example because it does not
+// expose one of the queried fields, are skipped; they must not block plans the apply would
+// accept. Not-found, permission and other errors are returned to the caller.
+func skipFailedLookup(ctx context.Context, lookup string, err error) bool {
+	if !ocpclient.IsQueryRejected(err) {
+		return false
+	}
+	tflog.Warn(ctx, "skipping plan-time check, the API rejected the lookup", map[string]interface{}{
+		"lookup": lookup,
+		"error":  err.Error(),
+	})
+	return true
+}
+
+// validateVirtualHostConstraints checks sizing against the template limits, and the tier against
+// the template's solution type and the tier options. A template or tier that does not exist is
+// reported as a plan error. The returned error is set when the lookup itself fails and is not
+// skipped (see skipFailedLookup).
+func validateVirtualHostConstraints(ctx context.Context, d *schema.ResourceDiff, client *ocpclient.Client) ([]error, error) {
+	var errs []error
+
+	var template *templateConstraints
+	if id, ok := knownString(d, "template_id"); ok {
+		var resp struct {
+			Template *templateConstraints `json:"template"`
+		}
+		err := client.DoContext(ctx, queryTemplateConstraints, map[string]interface{}{"id": id}, &resp)
+		switch {
+		case err != nil && skipFailedLookup(ctx, "template", err):
+			resp.Template = nil
+		case err != nil && !ocpclient.IsNotFound(err):
+			return nil, fmt.Errorf("failed to look up template %q: %w", id, err)
+		case err != nil, resp.Template == nil:
+			resp.Template = nil
+			errs = append(errs, fmt.Errorf("template_id: template %q not found", id))
+		}
+		template = resp.Template
+	}
+
+	if template != nil {
+		errs = append(errs, template.check(d)...)
+	}
+
+	if id, ok := knownString(d, "tier_id"); ok && (d.Id() == "" || d.HasChanges("tier_id", "template_id", "tier_extended", "target_iops")) {
+		var resp struct {
+			Tier *tierConstraints `json:"tier"`
+		}
+		err := client.DoContext(ctx, queryTierConstraints, map[string]interface{}{"id": id}, &resp)
+		switch {
+		case err != nil && skipFailedLookup(ctx, "tier", err):
+			resp.Tier = nil
+		case err != nil && !ocpclient.IsNotFound(err):
+			return nil, fmt.Errorf("failed to look up tier %q: %w", id, err)
+		case err != nil, resp.Tier == nil:
+			resp.Tier = nil
+			errs = append(errs, fmt.Errorf("tier_id: tier %q not found", id))
+		case template != nil && template.SolutionType != "" && resp.Tier.SolutionType != "" &&
+			!strings.EqualFold(template.SolutionType, resp.Tier.SolutionType):
+			errs = append(errs, fmt.Errorf("tier_id: tier %q is for solution type %s, but template %q is for %s",
+				resp.Tier.Name, resp.Tier.SolutionType, template.Name, template.SolutionType))
+		}
+		if resp.Tier != nil {
+			errs = append(errs, resp.Tier.check(d)...)
+		}
+	}
+
+	return errs, nil
+}
+
+// check reports planned sizing outside the template limits.
+func (t *templateConstraints) check(d *schema.ResourceDiff) []error {
+	var errs []error
+
+	if cpus, ok := knownInt(d, "cpu_count"); ok {
+		if t.MinCPUCount != nil && cpus < *t.MinCPUCount {
+			errs = append(errs, fmt.Errorf("cpu_count: %d is below the minimum of %d for template %q", cpus, *t.MinCPUCount, t.Name))
+		}
+		if t.MaxCPUCount != nil && cpus > *t.MaxCPUCount {
+			errs = append(errs, fmt.Errorf("cpu_count: %d exceeds the maximum of %d for template %q", cpus, *t.MaxCPUCount, t.Name))
+		}
+	}
+	if cores, ok := knownInt(d, "cores_per_socket"); ok && t.MaxCoresPerSocket != nil && cores > *t.MaxCoresPerSocket {
+		errs = append(errs, fmt.Errorf("cores_per_socket: %d exceeds the maximum of %d for template %q", cores, *t.MaxCoresPerSocket, t.Name))
+	}
+	if memory, ok := knownInt(d, "memory_size_gb"); ok {
+		if t.MinMemorySizeGB != nil && memory < *t.MinMemorySizeGB {
+			errs = append(errs, fmt.Errorf("memory_size_gb: %d GB is below the minimum of %d GB for template %q", memory, *t.MinMemorySizeGB, t.Name))
+		}
+		if t.MaxMemorySizeGB != nil && memory > *t.MaxMemorySizeGB {
+			errs = append(errs, fmt.Errorf("memory_size_gb: %d GB exceeds the maximum of %d GB for template %q", memory, *t.MaxMemorySizeGB, t.Name))
+		}
+	}
+
+	return errs
+}
+
+const queryVirtualHostHotAdd = `
+query VirtualHostHotAdd($id: GlobalID!) {
+  virtualHost(id: $id) {
+    id
+    powerState
+    cpuHotAddEnabled
+    memoryHotAddEnabled
+  }
+}
+`
+
+// customizeVirtualHostResizeDiff works out whether a planned resize restarts the virtual host
+// and records it in resize_restart_required. A resize that needs a restart fails the plan
+// when allow_resize_restart is false.
+//
+// CustomizeDiff cannot return warning diagnostics, so the restart is surfaced through the
+// planned resize_restart_required value and a warning log entry; the apply reports it again
+// as a warning diagnostic. When the API rejects the hot-add lookup, resize_restart_required
+// stays unknown and allow_resize_restart is only enforced by the API at apply time.
+func customizeVirtualHostResizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChanges("cpu_count", "cores_per_socket", "memory_size_gb") {
+		return nil
+	}
+	for _, key := range []string{"cpu_count", "cores_per_socket", "memory_size_gb"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("resize_restart_required")
+		}
+	}
+
+	client := meta.(*ocpclient.Client)
+	reasons, err := resizeRestartReasons(ctx, d, client)
+	if err != nil {
+		if skipFailedLookup(ctx, "virtual host hot-add settings", err) {
+			return d.SetNewComputed("resize_restart_required")
+		}
+		return err
+	}
+	if err := d.SetNew("resize_restart_required", len(reasons) > 0); err != nil {
+		return err
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	if !d.Get("allow_resize_restart").(bool) {
+		return fmt.Errorf("the planned resize requires restarting the virtual host (%s), but allow_resize_restart is false; "+
+			"set allow_resize_restart = true to accept the restart", strings.Join(reasons, "; "))
+	}
+	tflog.Warn(ctx, "planned resize restarts the virtual host", map[string]interface{}{
+		"virtual_host": d.Id(),
+		"reasons":      reasons,
+	})
+	return nil
+}
+
+// resizeRestartReasons lists why the planned resize cannot be applied to the running virtual host.
+// A powered-off virtual host is never restarted.
+func resizeRestartReasons(ctx context.Context, d *schema.ResourceDiff, client *ocpclient.Client) ([]string, error) {
+	var resp struct {
+		VirtualHost *struct {
+			PowerState          string `json:"powerState"`
+			CPUHotAddEnabled    bool   `json:"cpuHotAddEnabled"`
+			MemoryHotAddEnabled bool   `json:"memoryHotAddEnabled"`
+		} `json:"virtualHost"`
+	}
+	if err := client.DoContext(ctx, queryVirtualHostHotAdd, map[string]interface{}{"id": d.Id()}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to look up virtual host %s: %w", d.Id(), err)
+	}
+	// A virtual host deleted outside Terraform is recreated, not resized.
+	if resp.VirtualHost == nil || flattenPowerState(resp.VirtualHost.PowerState) == powerStateOff {
+		return nil, nil
+	}
+	vh := resp.VirtualHost
+
+	var reasons []string
+	if d.HasChange("cores_per_socket") {
+		oldCores, newCores := d.GetChange("cores_per_socket")
+		reasons = append(reasons, fmt.Sprintf("cores_per_socket changes from %d to %d", oldCores, newCores))
+	}
+
+	oldCPU, newCPU := d.GetChange("cpu_count")
+	switch {
+	case newCPU.(int) < oldCPU.(int):
+		reasons = append(reasons, fmt.Sprintf("cpu_count decreases from %d to %d", oldCPU, newCPU))
+	case newCPU.(int) > oldCPU.(int) && !vh.CPUHotAddEnabled:
+		reasons = append(reasons, "CPU hot-add is disabled")
+	}
+
+	oldMemory, newMemory := d.GetChange("memory_size_gb")
+	switch {
+	case newMemory.(int) < oldMemory.(int):
+		reasons = append(reasons, fmt.Sprintf("memory_size_gb decreases from %d to %d", oldMemory, newMemory))
+	case newMemory.(int) > oldMemory.(int) && !vh.MemoryHotAddEnabled:
+		reasons = append(reasons, "memory hot-add is disabled")
+	}
+
+	return reasons, nil
+}
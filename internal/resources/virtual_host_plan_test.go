@@ -0,0 +1,404 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+func planTestConfig(overrides map[string]interface{}) map[string]interface{} {
+	raw := map[string]interface{}{
+		"region":                 "FINLAND",
+		"customer_id":            "customer-1",
+		"project_id":             "project-1",
+		"hostname":               "app-1",
+		"domain_id":              "domain-1",
+		"cpu_count":              4,
+		"cores_per_socket":       2,
+		"memory_size_gb":         16,
+		"tier_id":                "tier-1",
+		"template_id":            "template-1",
+		"note":                   "managed-by-terraform",
+		"data_protection_policy": "policy-1",
+		"interfaces": []interface{}{
+			map[string]interface{}{"network_id": "net-1"},
+		},
+		"disk": []interface{}{
+			map[string]interface{}{"size_gb": 50, "tier_id": "tier-1"},
+		},
+	}
+	for k, v := range overrides {
+		raw[k] = v
+	}
+	return raw
+}
+
+// planAPI describes what constraintsServer reports about the tier and the existing virtual host.
+type planAPI struct {
+	tierSolutionType string
+	tierExtended     bool
+	powerState       string
+	cpuHotAdd        bool
+	memoryHotAdd     bool
+	// rejectQueries answers every lookup with a GraphQL error, as for fields the API lacks.
+	rejectQueries bool
+	// lookupError, when set, answers every lookup with this GraphQL error entry instead.
+	lookupError map[string]interface{}
+}
+
+// constraintsServer answers template, tier and hot-add lookups and counts them.
+func constraintsServer(t *testing.T, api planAPI, lookups *int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		*lookups++
+
+		var response map[string]interface{}
+		switch {
+		case api.lookupError != nil:
+			response = map[string]interface{}{
+				"data":   nil,
+				"errors": []interface{}{api.lookupError},
+			}
+		case api.rejectQueries:
+			response = map[string]interface{}{
+				"errors": []interface{}{
+					map[string]interface{}{"message": "Cannot query field 'minIops' on type 'TierNode'."},
+				},
+			}
+		case strings.Contains(body.Query, "template(id:"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"template": map[string]interface{}{
+						"id":                "template-1",
+						"name":              "rhel9",
+						"solutionType":      "OCP",
+						"minCpuCount":       1,
+						"maxCpuCount":       16,
+						"maxCoresPerSocket": 8,
+						"minMemorySizeGB":   2,
+						"maxMemorySizeGB":   64,
+					},
+				},
+			}
+		case strings.Contains(body.Query, "tier(id:"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"tier": map[string]interface{}{
+						"id":               "tier-1",
+						"name":             "gold",
+						"solutionType":     api.tierSolutionType,
+						"supportsExtended": api.tierExtended,
+						"minIops":          1000,
+						"maxIops":          20000,
+					},
+				},
+			}
+		case strings.Contains(body.Query, "virtualHost(id:"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHost": map[string]interface{}{
+						"id":                  "vh-1",
+						"powerState":          api.powerState,
+						"cpuHotAddEnabled":    api.cpuHotAdd,
+						"memoryHotAddEnabled": api.memoryHotAdd,
+					},
+				},
+			}
+		default:
+			t.Fatalf("unexpected query: %s", body.Query)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+}
+
+func TestResourceVirtualHostCustomizeDiff(t *testing.T) {
+	testCases := []struct {
+		name        string
+		existing    bool
+		prior       map[string]interface{}
+		overrides   map[string]interface{}
+		api         planAPI
+		wantError   string
+		wantLookups bool
+		// wantRestart is the planned resize_restart_required, or "unknown".
+		wantRestart string
+	}{
+		{
+			name:        "valid create",
+			wantLookups: true,
+		},
+		{
+			name:      "cpu not divisible by cores per socket",
+			overrides: map[string]interface{}{"cpu_count": 3},
+			wantError: "cpu_count (3) must be a multiple of cores_per_socket (2)",
+		},
+		{
+			name:      "memory above template maximum",
+			overrides: map[string]interface{}{"memory_size_gb": 128},
+			wantError: `memory_size_gb: 128 GB exceeds the maximum of 64 GB for template "rhel9"`,
+		},
+		{
+			name:      "tier for another solution type",
+			api:       planAPI{tierSolutionType: "CAAS"},
+			wantError: `tier "gold" is for solution type CAAS, but template "rhel9" is for OCP`,
+		},
+		{
+			name:      "extended tier not supported",
+			overrides: map[string]interface{}{"tier_extended": true},
+			wantError: `tier_extended: tier "gold" does not support the extended variant`,
+		},
+		{
+			name:        "target iops within tier limits",
+			overrides:   map[string]interface{}{"tier_extended": true, "target_iops": 5000},
+			api:         planAPI{tierExtended: true},
+			wantLookups: true,
+		},
+		{
+			name:      "target iops above tier maximum",
+			overrides: map[string]interface{}{"tier_extended": true, "target_iops": 50000},
+			api:       planAPI{tierExtended: true},
+			wantError: `target_iops: 50000 exceeds the maximum of 20000 for tier "gold"`,
+		},
+		{
+			name:      "target iops without extended tier",
+			overrides: map[string]interface{}{"target_iops": 5000},
+			api:       planAPI{tierExtended: true},
+			wantError: "target_iops requires tier_extended = true",
+		},
+		{
+			name:        "lookups rejected",
+			overrides:   map[string]interface{}{"memory_size_gb": 128},
+			api:         planAPI{rejectQueries: true},
+			wantLookups: true,
+		},
+		{
+			name: "template not found",
+			api: planAPI{lookupError: map[string]interface{}{
+				"message": "Template matching query does not exist.",
+				"path":    []interface{}{"template"},
+			}},
+			wantError: `template_id: template "template-1" not found`,
+		},
+		{
+			name: "lookup unauthorized",
+			api: planAPI{lookupError: map[string]interface{}{
+				"message":    "Authentication required",
+				"extensions": map[string]interface{}{"code": "UNAUTHENTICATED"},
+			}},
+			wantError: "failed to look up template",
+		},
+		{
+			name:      "lookups rejected keeps local checks",
+			overrides: map[string]interface{}{"target_iops": 5000},
+			api:       planAPI{rejectQueries: true},
+			wantError: "target_iops requires tier_extended = true",
+		},
+		{
+			name:        "update without sizing changes",
+			existing:    true,
+			overrides:   map[string]interface{}{"note": "changed"},
+			wantLookups: false,
+		},
+		{
+			name:        "update resize",
+			existing:    true,
+			overrides:   map[string]interface{}{"cpu_count": 8},
+			api:         planAPI{powerState: "POWERED_ON", cpuHotAdd: true},
+			wantLookups: true,
+			wantRestart: "false",
+		},
+		{
+			name:        "resize without cpu hot-add",
+			existing:    true,
+			overrides:   map[string]interface{}{"cpu_count": 8},
+			api:         planAPI{powerState: "POWERED_ON", memoryHotAdd: true},
+			wantLookups: true,
+			wantRestart: "true",
+		},
+		{
+			name:        "memory shrink",
+			existing:    true,
+			overrides:   map[string]interface{}{"memory_size_gb": 8},
+			api:         planAPI{powerState: "POWERED_ON", cpuHotAdd: true, memoryHotAdd: true},
+			wantLookups: true,
+			wantRestart: "true",
+		},
+		{
+			name:        "memory shrink while powered off",
+			existing:    true,
+			overrides:   map[string]interface{}{"memory_size_gb": 8},
+			api:         planAPI{powerState: "POWERED_OFF"},
+			wantLookups: true,
+			wantRestart: "false",
+		},
+		{
+			name:      "restart not allowed",
+			existing:  true,
+			overrides: map[string]interface{}{"cpu_count": 8, "allow_resize_restart": false},
+			api:       planAPI{powerState: "POWERED_ON"},
+			wantError: "allow_resize_restart is false",
+		},
+		{
+			name:        "hot-add lookup rejected",
+			existing:    true,
+			overrides:   map[string]interface{}{"cpu_count": 8, "allow_resize_restart": false},
+			api:         planAPI{rejectQueries: true},
+			wantLookups: true,
+			wantRestart: "unknown",
+		},
+		{
+			name:      "disk shrink",
+			existing:  true,
+			overrides: map[string]interface{}{"disk": []interface{}{map[string]interface{}{"size_gb": 10, "tier_id": "tier-1"}}},
+			wantError: "disk.0: size_gb cannot be decreased from 50 to 10",
+		},
+		{
+			name:     "middle disk removed",
+			existing: true,
+			prior: map[string]interface{}{"disk": []interface{}{
+				map[string]interface{}{"size_gb": 100, "tier_id": "tier-1"},
+				map[string]interface{}{"size_gb": 200, "tier_id": "tier-1"},
+				map[string]interface{}{"size_gb": 300, "tier_id": "tier-1"},
+			}},
+			overrides: map[string]interface{}{"disk": []interface{}{
+				map[string]interface{}{"size_gb": 100, "tier_id": "tier-1"},
+				map[string]interface{}{"size_gb": 300, "tier_id": "tier-1"},
+			}},
+			wantError: "disk.1: disk blocks are matched by position",
+		},
+		{
+			name:     "middle interface removed",
+			existing: true,
+			prior: map[string]interface{}{"interfaces": []interface{}{
+				map[string]interface{}{"network_id": "net-1"},
+				map[string]interface{}{"network_id": "net-2"},
+				map[string]interface{}{"network_id": "net-3"},
+			}},
+			overrides: map[string]interface{}{"interfaces": []interface{}{
+				map[string]interface{}{"network_id": "net-1"},
+				map[string]interface{}{"network_id": "net-3"},
+			}},
+			wantError: "interfaces.1: interfaces blocks are matched by position",
+		},
+		{
+			name:     "last disk removed",
+			existing: true,
+			prior: map[string]interface{}{"disk": []interface{}{
+				map[string]interface{}{"size_gb": 50, "tier_id": "tier-1"},
+				map[string]interface{}{"size_gb": 200, "tier_id": "tier-1"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.api.tierSolutionType == "" {
+				tc.api.tierSolutionType = "OCP"
+			}
+			lookups := 0
+			server := constraintsServer(t, tc.api, &lookups)
+			defer server.Close()
+
+			client := ocpclient.New(server.URL, "token", true)
+			res := ResourceVirtualHost()
+
+			var state *terraform.InstanceState
+			if tc.existing {
+				data := schema.TestResourceDataRaw(t, res.Schema, planTestConfig(tc.prior))
+				data.SetId("vh-1")
+				state = data.State()
+			}
+
+			diff, err := res.Diff(context.Background(), state, terraform.NewResourceConfigRaw(planTestConfig(tc.overrides)), client)
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (lookups > 0) != tc.wantLookups {
+				t.Fatalf("expected API lookups %t, got %d", tc.wantLookups, lookups)
+			}
+			if tc.wantRestart != "" {
+				attr := diff.Attributes["resize_restart_required"]
+				if tc.wantRestart == "unknown" {
+					if attr == nil || !attr.NewComputed {
+						t.Fatalf("expected resize_restart_required to be unknown, got %+v", attr)
+					}
+				} else if attr == nil || attr.New != tc.wantRestart {
+					t.Fatalf("expected resize_restart_required %s, got %+v", tc.wantRestart, attr)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceVirtualHostDiffKeepsUnmanagedDisks(t *testing.T) {
+	res := ResourceVirtualHost()
+	data := schema.TestResourceDataRaw(t, res.Schema, planTestConfig(nil))
+	data.SetId("vh-1")
+	// Read writes every data disk reported by the API, including ones the configuration never declared.
+	_ = data.Set("disk", []interface{}{
+		map[string]interface{}{"id": "disk-1", "size_gb": 50, "tier_id": "tier-1"},
+		map[string]interface{}{"id": "disk-2", "size_gb": 500, "tier_id": "tier-1"},
+	})
+
+	raw := planTestConfig(nil)
+	delete(raw, "disk")
+	diff, err := res.Diff(context.Background(), data.State(), terraform.NewResourceConfigRaw(raw), ocpclient.New("http://127.0.0.1:0", "token", true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != nil {
+		for key := range diff.Attributes {
+			if strings.HasPrefix(key, "disk") {
+				t.Fatalf("expected no disk changes without disk blocks, got %s: %+v", key, diff.Attributes[key])
+			}
+		}
+	}
+}
+
+func TestResourceVirtualHostDiffKeepsImportedInterfaceAddress(t *testing.T) {
+	res := ResourceVirtualHost()
+	data := schema.TestResourceDataRaw(t, res.Schema, planTestConfig(nil))
+	data.SetId("vh-1")
+	// After import there is no prior block to tell whether the address was assigned statically.
+	_ = data.Set("interfaces", flattenInterfaces(nil, []apiNetworkInterface{{
+		ID:            "nic-1",
+		Network:       struct{ ID string }{ID: "net-1"},
+		IPv4Addresses: []apiIPAddress{{IP: "192.0.2.10", Prefixlen: 24}},
+	}}))
+
+	diff, err := res.Diff(context.Background(), data.State(), terraform.NewResourceConfigRaw(planTestConfig(nil)), ocpclient.New("http://127.0.0.1:0", "token", true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff != nil {
+		for key := range diff.Attributes {
+			if strings.HasPrefix(key, "interfaces") {
+				t.Fatalf("expected no interface changes after import, got %s: %+v", key, diff.Attributes[key])
+			}
+		}
+	}
+}
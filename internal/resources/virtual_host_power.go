@@ -0,0 +1,78 @@
+package resources
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+// Terraform values of power_state.
+const (
+	powerStateOn        = "on"
+	powerStateOff       = "off"
+	powerStateSuspended = "suspended"
+)
+
+// powerStateAPIValues maps power_state to the VirtualHostPowerState enum of the API.
+var powerStateAPIValues = map[string]string{
+	powerStateOn:        "POWERED_ON",
+	powerStateOff:       "POWERED_OFF",
+	powerStateSuspended: "SUSPENDED",
+}
+
+const mutationSetPowerState = `
+mutation SetVmPowerState($input: VirtualHostSetPowerStateInput!) {
+  virtualHostSetPowerState(input: $input) {
+    __typename
+    ... on TaskExecutionNode {
+      id
+    }` + payloadErrorSelection + `  }
+}
+`
+
+// powerStateSchema is the power_state attribute shared by ocp_virtual_host and ocp_virtual_host_immutable.
+func powerStateSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Description:  "Power state of the virtual host: `on`, `off` or `suspended`. When not set, the current power state is only read.",
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.StringInSlice([]string{powerStateOn, powerStateOff, powerStateSuspended}, false),
+	}
+}
+
+// virtualHostPowerStep powers the virtual host on or off, or suspends it.
+var virtualHostPowerStep = virtualHostUpdateStep{
+	keys: []string{"power_state"},
+	apply: func(ctx context.Context, d *schema.ResourceData, client *ocpclient.Client) diag.Diagnostics {
+		return setVirtualHostPowerState(ctx, client, d.Id(), d.Get("power_state").(string))
+	},
+}
+
+// flattenPowerState maps the API power state to power_state. Unknown values are passed through
+// in lower case so that they show up as drift.
+func flattenPowerState(apiState string) string {
+	for state, value := range powerStateAPIValues {
+		if strings.EqualFold(apiState, value) {
+			return state
+		}
+	}
+	return strings.ToLower(apiState)
+}
+
+// setVirtualHostPowerState changes the power state and waits for the resulting task.
+func setVirtualHostPowerState(ctx context.Context, client *ocpclient.Client, id, state string) diag.Diagnostics {
+	value, ok := powerStateAPIValues[state]
+	if !ok {
+		return diag.Errorf("virtualHostSetPowerState: unsupported power_state %q", state)
+	}
+	return runTaskMutation(ctx, client, mutationSetPowerState, "virtualHostSetPowerState", map[string]interface{}{
+		"virtualHost": id,
+		"powerState":  value,
+	})
+}
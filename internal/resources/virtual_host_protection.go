@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// deletionProtectionSchema is the deletion_protection attribute shared by all virtual host resources.
+// It is Terraform-only: the API has no lock flag, so the provider enforces it.
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: "Prevent the virtual host from being destroyed or replaced. Set it to `false` and apply before destroying or replacing the virtual host.",
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+// checkDeletionProtection refuses to delete a virtual host whose state has deletion_protection enabled.
+func checkDeletionProtection(d *schema.ResourceData) diag.Diagnostics {
+	if !d.Get("deletion_protection").(bool) {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Virtual host is protected from deletion",
+		Detail: fmt.Sprintf("Virtual host %s has deletion_protection enabled. "+
+			"Set deletion_protection = false and apply before destroying it.", d.Id()),
+	}}
+}
+
+// customizeDeletionProtectionDiff fails plans that replace a virtual host with deletion_protection
+// enabled in state. Replacement deletes the existing virtual host with its prior state, so the
+// protection must be turned off in an earlier apply.
+func customizeDeletionProtectionDiff(resource func() *schema.Resource) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if d.Id() == "" {
+			return nil
+		}
+		if protected, _ := d.GetChange("deletion_protection"); !protected.(bool) {
+			return nil
+		}
+
+		var replacing []string
+		for key, s := range resource().Schema {
+			if s.ForceNew && d.HasChange(key) {
+				replacing = append(replacing, key)
+			}
+		}
+		if len(replacing) == 0 {
+			return nil
+		}
+
+		sort.Strings(replacing)
+		return fmt.Errorf("changing %s replaces virtual host %s, which has deletion_protection enabled; "+
+			"set deletion_protection = false and apply before replacing it", strings.Join(replacing, ", "), d.Id())
+	}
+}
@@ -0,0 +1,125 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+func TestResourceVirtualHostDeleteProtected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected API call for a protected virtual host")
+	}))
+	defer server.Close()
+	client := ocpclient.New(server.URL, "token", true)
+
+	testCases := []struct {
+		name   string
+		res    *schema.Resource
+		raw    map[string]interface{}
+		delete func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+	}{
+		{
+			name:   "ocp_virtual_host",
+			res:    ResourceVirtualHost(),
+			raw:    planTestConfig(map[string]interface{}{"deletion_protection": true}),
+			delete: ResourceVirtualHostDelete,
+		},
+		{
+			name: "ocp_virtual_host_caas",
+			res:  ResourceVirtualHostCaas(),
+			raw: map[string]interface{}{
+				"region":              "FINLAND",
+				"vcenter_id":          "vcenter-1",
+				"project_id":          "project-1",
+				"tier_id":             "tier-1",
+				"hostname":            "app-1",
+				"uuid":                "uuid-1",
+				"note":                "shadow",
+				"deletion_protection": true,
+			},
+			delete: resourceVirtualHostCaasDelete,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			data := schema.TestResourceDataRaw(t, tc.res.Schema, tc.raw)
+			data.SetId("vh-1")
+
+			diags := tc.delete(context.Background(), data, client)
+			if !diags.HasError() || !strings.Contains(diags[0].Detail, "deletion_protection") {
+				t.Fatalf("expected deletion protection error, got %v", diags)
+			}
+			if data.Id() != "vh-1" {
+				t.Fatalf("expected id to be kept, got %q", data.Id())
+			}
+		})
+	}
+}
+
+func TestResourceVirtualHostDeletionProtectionDiff(t *testing.T) {
+	testCases := []struct {
+		name      string
+		protected bool
+		overrides map[string]interface{}
+		wantError string
+	}{
+		{
+			name:      "protected in-place update",
+			protected: true,
+			overrides: map[string]interface{}{"note": "changed", "deletion_protection": true},
+		},
+		{
+			name:      "protected replacement",
+			protected: true,
+			overrides: map[string]interface{}{"hostname": "app-2", "deletion_protection": true},
+			wantError: "changing hostname replaces virtual host vh-1, which has deletion_protection enabled",
+		},
+		{
+			name:      "protection disabled in the same plan",
+			protected: true,
+			overrides: map[string]interface{}{"hostname": "app-2"},
+			wantError: "deletion_protection enabled",
+		},
+		{
+			name:      "unprotected replacement",
+			overrides: map[string]interface{}{"hostname": "app-2", "deletion_protection": true},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			lookups := 0
+			server := constraintsServer(t, planAPI{tierSolutionType: "OCP"}, &lookups)
+			defer server.Close()
+
+			client := ocpclient.New(server.URL, "token", true)
+			res := ResourceVirtualHost()
+
+			data := schema.TestResourceDataRaw(t, res.Schema, planTestConfig(map[string]interface{}{"deletion_protection": tc.protected}))
+			data.SetId("vh-1")
+
+			_, err := res.Diff(context.Background(), data.State(), terraform.NewResourceConfigRaw(planTestConfig(tc.overrides)), client)
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
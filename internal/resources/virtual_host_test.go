@@ -3,11 +3,14 @@ package resources
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
@@ -24,6 +27,15 @@ func resourceDataWithState(t *testing.T, res *schema.Resource, state *terraform.
 		t.Fatalf("diff: %v", err)
 	}
 
+	// Terraform sends the configuration alongside the diff; GetRawConfig reads it from there.
+	rawJSON, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if diff.RawConfig, err = ctyjson.Unmarshal(rawJSON, sm.CoreConfigSchema().ImpliedType()); err != nil {
+		t.Fatalf("raw config: %v", err)
+	}
+
 	rd, err := sm.Data(state, diff)
 	if err != nil {
 		t.Fatalf("data: %v", err)
@@ -32,6 +44,19 @@ func resourceDataWithState(t *testing.T, res *schema.Resource, state *terraform.
 	return rd
 }
 
+// taskExecutionResponse builds a GetTaskExecution response body.
+func taskExecutionResponse(id, state, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"taskExecution": map[string]interface{}{
+				"id":      id,
+				"state":   state,
+				"message": message,
+			},
+		},
+	}
+}
+
 func TestResourceVirtualHostCreate(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body struct {
@@ -121,6 +146,114 @@ func TestResourceVirtualHostCreate(t *testing.T) {
 	}
 }
 
+func TestResourceVirtualHostCreateWaitForState(t *testing.T) {
+	testCases := []struct {
+		name      string
+		states    []string
+		wantError string
+		wantState string
+	}{
+		{
+			name:      "reaches target",
+			states:    []string{"PROVISIONING", "PROVISIONING", "RUNNING"},
+			wantState: "RUNNING",
+		},
+		{
+			name:      "provisioning failed",
+			states:    []string{"PROVISIONING", "DEPLOYMENT_FAILED"},
+			wantError: "failed with status DEPLOYMENT_FAILED",
+			wantState: "DEPLOYMENT_FAILED",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var polls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query string `json:"query"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request: %v", err)
+				}
+
+				var response map[string]interface{}
+				switch {
+				case strings.Contains(body.Query, "virtualHostCreate"):
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							"virtualHostCreate": map[string]interface{}{
+								"__typename": "VirtualHostCreated",
+								"virtualHost": map[string]interface{}{
+									"id":    "vh-1",
+									"state": "PROVISIONING",
+								},
+							},
+						},
+					}
+				case strings.Contains(body.Query, "virtualHost(id"):
+					state := tc.states[polls]
+					polls++
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							"virtualHost": map[string]interface{}{
+								"id":    "vh-1",
+								"state": state,
+							},
+						},
+					}
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Fatalf("encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+			data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, map[string]interface{}{
+				"region":                 "FINLAND",
+				"customer_id":            "customer-1",
+				"project_id":             "project-1",
+				"hostname":               "app-1",
+				"domain_id":              "domain-1",
+				"cpu_count":              2,
+				"memory_size_gb":         8,
+				"tier_id":                "tier-1",
+				"template_id":            "template-1",
+				"note":                   "managed-by-terraform",
+				"data_protection_policy": "policy-1",
+				"wait_for_state":         "running",
+				"interfaces": []interface{}{
+					map[string]interface{}{"network_id": "net-1"},
+				},
+			})
+
+			diags := ResourceVirtualHostCreate(context.Background(), data, client)
+			if tc.wantError == "" && diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags[0].Summary)
+			}
+			if tc.wantError != "" && (!diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError)) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantError, diags)
+			}
+
+			// A failed wait keeps the ID so Terraform taints the resource.
+			if data.Id() != "vh-1" {
+				t.Fatalf("expected id vh-1, got %q", data.Id())
+			}
+			if got := data.Get("status").(string); got != tc.wantState {
+				t.Fatalf("expected status %s, got %q", tc.wantState, got)
+			}
+			if polls != len(tc.states) {
+				t.Fatalf("expected %d polls, got %d", len(tc.states), polls)
+			}
+		})
+	}
+}
+
 func TestResourceVirtualHostReadNotFound(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := map[string]interface{}{
@@ -161,6 +294,140 @@ func TestResourceVirtualHostReadNotFound(t *testing.T) {
 	}
 }
 
+func TestResourceVirtualHostReadGraphQLNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": nil,
+			"errors": []interface{}{
+				map[string]interface{}{
+					"message": "VirtualHost matching query does not exist.",
+					"path":    []interface{}{"virtualHost"},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := ocpclient.New(server.URL, "token", true)
+	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, map[string]interface{}{
+		"region":                 "FINLAND",
+		"customer_id":            "customer-1",
+		"project_id":             "project-1",
+		"hostname":               "app-1",
+		"domain_id":              "domain-1",
+		"cpu_count":              2,
+		"memory_size_gb":         8,
+		"tier_id":                "tier-1",
+		"template_id":            "template-1",
+		"note":                   "managed-by-terraform",
+		"data_protection_policy": "policy-1",
+		"interfaces":             []interface{}{},
+	})
+	data.SetId("vh-1")
+
+	diags := ResourceVirtualHostRead(context.Background(), data, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+	if data.Id() != "" {
+		t.Fatalf("expected id to be cleared, got %q", data.Id())
+	}
+}
+
+func TestResourceVirtualHostReadNestedNotFoundKeepsID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"virtualHost": map[string]interface{}{
+					"id":                   "vh-1",
+					"dataProtectionPolicy": nil,
+				},
+			},
+			"errors": []interface{}{
+				map[string]interface{}{
+					"message": "DataProtectionPolicy matching query does not exist.",
+					"path":    []interface{}{"virtualHost", "dataProtectionPolicy"},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := ocpclient.New(server.URL, "token", true)
+	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, planTestConfig(nil))
+	data.SetId("vh-1")
+
+	diags := ResourceVirtualHostRead(context.Background(), data, client)
+	if !diags.HasError() {
+		t.Fatalf("expected the nested error to be reported")
+	}
+	if data.Id() != "vh-1" {
+		t.Fatalf("expected id to be kept, got %q", data.Id())
+	}
+}
+
+func TestResourceVirtualHostReadKeepsPlacementWhenRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		var response map[string]interface{}
+		switch {
+		case strings.Contains(body.Query, "GetVmPlacement"):
+			response = map[string]interface{}{
+				"errors": []interface{}{
+					map[string]interface{}{"message": "Field 'businessService' must have a selection of subfields."},
+				},
+			}
+		case strings.Contains(body.Query, "GetVm("):
+			if strings.Contains(body.Query, "dedicatedCluster") {
+				t.Fatalf("expected placement fields outside GetVm: %s", body.Query)
+			}
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHost": map[string]interface{}{
+						"id":    "vh-1",
+						"state": "ACTIVE",
+						"note":  "changed",
+					},
+				},
+			}
+		default:
+			t.Fatalf("unexpected query: %s", body.Query)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := ocpclient.New(server.URL, "token", true)
+	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, planTestConfig(map[string]interface{}{
+		"business_service": "BS-1234",
+	}))
+	data.SetId("vh-1")
+
+	if diags := ResourceVirtualHostRead(context.Background(), data, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+	if got := data.Get("note").(string); got != "changed" {
+		t.Fatalf("expected note to be refreshed, got %q", got)
+	}
+	if got := data.Get("business_service").(string); got != "BS-1234" {
+		t.Fatalf("expected business_service to be kept, got %q", got)
+	}
+}
+
 func TestResourceVirtualHostReadMapsInterfaces(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := map[string]interface{}{
@@ -258,11 +525,14 @@ func TestResourceVirtualHostReadMapsInterfaces(t *testing.T) {
 	if got := iface["network_id"].(string); got != "net-1" {
 		t.Fatalf("expected network_id net-1, got %q", got)
 	}
-	if got := iface["auto_assign_ip"].(bool); got {
-		t.Fatalf("expected auto_assign_ip false, got true")
+	if got := iface["auto_assign_ip"].(bool); !got {
+		t.Fatalf("expected auto_assign_ip true without a prior block, got false")
+	}
+	if got := iface["ip"].(string); got != "" {
+		t.Fatalf("expected no ip without a prior block, got %q", got)
 	}
-	if got := iface["ip"].(string); got != "192.0.2.10" {
-		t.Fatalf("expected ip 192.0.2.10, got %q", got)
+	if got := data.Get("interfaces.0.ipv4_addresses.0.address").(string); got != "192.0.2.10" {
+		t.Fatalf("expected ipv4 address 192.0.2.10, got %q", got)
 	}
 }
 
@@ -290,6 +560,10 @@ func TestResourceVirtualHostUpdateResize(t *testing.T) {
 			if err := json.NewEncoder(w).Encode(response); err != nil {
 				t.Fatalf("encode response: %v", err)
 			}
+		case strings.Contains(body.Query, "taskExecution"):
+			if err := json.NewEncoder(w).Encode(taskExecutionResponse("task-1", "SUCCESS", "")); err != nil {
+				t.Fatalf("encode response: %v", err)
+			}
 		case strings.Contains(body.Query, "virtualHost(id"):
 			response := map[string]interface{}{
 				"data": map[string]interface{}{
@@ -375,7 +649,7 @@ func TestResourceVirtualHostUpdateResize(t *testing.T) {
 	})
 	newData.SetId("vh-1")
 
-	client := ocpclient.New(server.URL, "token", true)
+	client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
 	diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
 	if diags.HasError() {
 		t.Fatalf("unexpected error: %v", diags[0].Summary)
@@ -409,6 +683,10 @@ func TestResourceVirtualHostUpdateTier(t *testing.T) {
 			if err := json.NewEncoder(w).Encode(response); err != nil {
 				t.Fatalf("encode response: %v", err)
 			}
+		case strings.Contains(body.Query, "taskExecution"):
+			if err := json.NewEncoder(w).Encode(taskExecutionResponse("task-2", "SUCCESS", "")); err != nil {
+				t.Fatalf("encode response: %v", err)
+			}
 		case strings.Contains(body.Query, "virtualHost(id"):
 			response := map[string]interface{}{
 				"data": map[string]interface{}{
@@ -494,7 +772,7 @@ func TestResourceVirtualHostUpdateTier(t *testing.T) {
 	})
 	newData.SetId("vh-1")
 
-	client := ocpclient.New(server.URL, "token", true)
+	client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
 	diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
 	if diags.HasError() {
 		t.Fatalf("unexpected error: %v", diags[0].Summary)
@@ -504,7 +782,8 @@ func TestResourceVirtualHostUpdateTier(t *testing.T) {
 	}
 }
 
-func TestResourceVirtualHostDelete(t *testing.T) {
+func TestResourceVirtualHostUpdateResizeThenTier(t *testing.T) {
+	var calls []string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body struct {
 			Query string `json:"query"`
@@ -512,17 +791,116 @@ func TestResourceVirtualHostDelete(t *testing.T) {
 		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 			t.Fatalf("decode request: %v", err)
 		}
-		if !strings.Contains(body.Query, "virtualHostDelete") {
-			w.WriteHeader(http.StatusBadRequest)
-			return
+
+		var response map[string]interface{}
+		switch {
+		case strings.Contains(body.Query, "virtualHostResize"):
+			calls = append(calls, "resize")
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHostResize": map[string]interface{}{
+						"__typename": "TaskExecutionNode",
+						"id":         "task-1",
+					},
+				},
+			}
+		case strings.Contains(body.Query, "virtualHostUpdateTier"):
+			calls = append(calls, "tier")
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHostUpdateTier": map[string]interface{}{
+						"__typename": "OperationUnavailable",
+						"message":    "storage migration already running",
+					},
+				},
+			}
+		case strings.Contains(body.Query, "taskExecution"):
+			calls = append(calls, "wait")
+			response = taskExecutionResponse("task-1", "SUCCESS", "")
+		default:
+			t.Fatalf("unexpected query: %s", body.Query)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
 		}
+	}))
+	defer server.Close()
 
-		response := map[string]interface{}{
-			"data": map[string]interface{}{
-				"virtualHostDelete": map[string]interface{}{
-					"__typename": "TaskExecutionNode",
+	res := ResourceVirtualHost()
+	raw := map[string]interface{}{
+		"region":                 "FINLAND",
+		"customer_id":            "customer-1",
+		"project_id":             "project-1",
+		"hostname":               "app-1",
+		"domain_id":              "domain-1",
+		"cpu_count":              2,
+		"cores_per_socket":       1,
+		"memory_size_gb":         8,
+		"tier_id":                "tier-1",
+		"template_id":            "template-1",
+		"note":                   "managed-by-terraform",
+		"data_protection_policy": "policy-1",
+		"interfaces": []interface{}{
+			map[string]interface{}{"network_id": "net-1"},
+		},
+	}
+	oldData := schema.TestResourceDataRaw(t, res.Schema, raw)
+	oldData.SetId("vh-1")
+
+	raw["cpu_count"] = 4
+	raw["tier_id"] = "tier-2"
+	newData := resourceDataWithState(t, res, oldData.State(), raw)
+	newData.SetId("vh-1")
+
+	client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+	diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+	if !diags.HasError() {
+		t.Fatalf("expected tier change to fail")
+	}
+	if got := strings.Join(calls, ","); got != "resize,wait,tier" {
+		t.Fatalf("unexpected call order %q", got)
+	}
+
+	// The completed resize is kept, the failed tier change is planned again.
+	state := newData.State()
+	if got := state.Attributes["cpu_count"]; got != "4" {
+		t.Fatalf("expected cpu_count 4 in state, got %q", got)
+	}
+	if got := state.Attributes["tier_id"]; got != "tier-1" {
+		t.Fatalf("expected tier_id tier-1 in state, got %q", got)
+	}
+}
+
+func TestResourceVirtualHostUpdateTaskFailed(t *testing.T) {
+	var polls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		var response map[string]interface{}
+		switch {
+		case strings.Contains(body.Query, "virtualHostResize"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHostResize": map[string]interface{}{
+						"__typename": "TaskExecutionNode",
+						"id":         "task-1",
+					},
 				},
-			},
+			}
+		case strings.Contains(body.Query, "taskExecution"):
+			polls++
+			if polls < 3 {
+				response = taskExecutionResponse("task-1", "RUNNING", "")
+			} else {
+				response = taskExecutionResponse("task-1", "FAILED", "not enough capacity in cluster")
+			}
+		default:
+			t.Fatalf("unexpected query after failed task: %s", body.Query)
 		}
 		if err := json.NewEncoder(w).Encode(response); err != nil {
 			t.Fatalf("encode response: %v", err)
@@ -530,8 +908,8 @@ func TestResourceVirtualHostDelete(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := ocpclient.New(server.URL, "token", true)
-	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, map[string]interface{}{
+	res := ResourceVirtualHost()
+	raw := map[string]interface{}{
 		"region":                 "FINLAND",
 		"customer_id":            "customer-1",
 		"project_id":             "project-1",
@@ -547,14 +925,935 @@ func TestResourceVirtualHostDelete(t *testing.T) {
 		"interfaces": []interface{}{
 			map[string]interface{}{"network_id": "net-1"},
 		},
-	})
-	data.SetId("vh-1")
+	}
+	oldData := schema.TestResourceDataRaw(t, res.Schema, raw)
+	oldData.SetId("vh-1")
 
-	diags := ResourceVirtualHostDelete(context.Background(), data, client)
-	if diags.HasError() {
-		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	raw["cpu_count"] = 4
+	newData := resourceDataWithState(t, res, oldData.State(), raw)
+	newData.SetId("vh-1")
+
+	client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+	diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+	if !diags.HasError() {
+		t.Fatalf("expected error for failed task")
 	}
-	if data.Id() != "" {
-		t.Fatalf("expected id to be cleared, got %q", data.Id())
+	if diags[0].Summary != "OCP task failed" {
+		t.Fatalf("unexpected summary %q", diags[0].Summary)
+	}
+	if !strings.Contains(diags[0].Detail, "not enough capacity in cluster") {
+		t.Fatalf("expected task message in detail, got %q", diags[0].Detail)
+	}
+	if polls != 3 {
+		t.Fatalf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestResourceVirtualHostDelete(t *testing.T) {
+	var lookups int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		var response map[string]interface{}
+		switch {
+		case strings.Contains(body.Query, "virtualHostDelete"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHostDelete": map[string]interface{}{
+						"__typename": "TaskExecutionNode",
+						"id":         "task-3",
+					},
+				},
+			}
+		case strings.Contains(body.Query, "taskExecution"):
+			response = taskExecutionResponse("task-3", "SUCCESS", "")
+		case strings.Contains(body.Query, "virtualHost(id"):
+			lookups++
+			var vh interface{}
+			if lookups < 2 {
+				vh = map[string]interface{}{"id": "vh-1", "state": "DELETING"}
+			}
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHost": vh,
+				},
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, map[string]interface{}{
+		"region":                 "FINLAND",
+		"customer_id":            "customer-1",
+		"project_id":             "project-1",
+		"hostname":               "app-1",
+		"domain_id":              "domain-1",
+		"cpu_count":              2,
+		"cores_per_socket":       1,
+		"memory_size_gb":         8,
+		"tier_id":                "tier-1",
+		"template_id":            "template-1",
+		"note":                   "managed-by-terraform",
+		"data_protection_policy": "policy-1",
+		"interfaces": []interface{}{
+			map[string]interface{}{"network_id": "net-1"},
+		},
+	})
+	data.SetId("vh-1")
+
+	diags := ResourceVirtualHostDelete(context.Background(), data, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+	if data.Id() != "" {
+		t.Fatalf("expected id to be cleared, got %q", data.Id())
+	}
+	if lookups != 2 {
+		t.Fatalf("expected 2 lookups, got %d", lookups)
+	}
+}
+
+func TestResourceVirtualHostDeleteValidationErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"virtualHostDelete": map[string]interface{}{
+					"__typename": "ValidationErrors",
+					"message":    "virtual host is locked",
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := ocpclient.New(server.URL, "token", true)
+	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, map[string]interface{}{})
+	data.SetId("vh-1")
+
+	diags := ResourceVirtualHostDelete(context.Background(), data, client)
+	if !diags.HasError() {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(diags[0].Summary, "virtual host is locked") {
+		t.Fatalf("unexpected summary %q", diags[0].Summary)
+	}
+	if data.Id() != "vh-1" {
+		t.Fatalf("expected id to be kept, got %q", data.Id())
+	}
+}
+
+func TestResourceVirtualHostUpdateInterfaces(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    []interface{}
+		calls     []string
+		wantError string
+	}{
+		{
+			name: "change ip and attach network",
+			config: []interface{}{
+				map[string]interface{}{"network_id": "net-1", "auto_assign_ip": false, "ip": "192.0.2.20"},
+				map[string]interface{}{"network_id": "net-2"},
+				map[string]interface{}{"network_id": "net-3"},
+			},
+			calls: []string{
+				"networkInterfaceCreate virtualHost=vh-1 network=net-3 ipList=[]",
+				"networkInterfaceUpdate networkInterface=nic-1 network=net-1 ipList=[192.0.2.20]",
+			},
+		},
+		{
+			name: "detach network",
+			config: []interface{}{
+				map[string]interface{}{"network_id": "net-1"},
+			},
+			calls: []string{
+				"networkInterfaceDelete networkInterface=nic-2",
+			},
+		},
+		{
+			name: "detach first network",
+			config: []interface{}{
+				map[string]interface{}{"network_id": "net-2"},
+			},
+			wantError: "interfaces.0: interfaces blocks are matched by position",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query     string `json:"query"`
+					Variables struct {
+						Input map[string]interface{} `json:"input"`
+					} `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request: %v", err)
+				}
+
+				var response map[string]interface{}
+				switch {
+				case strings.Contains(body.Query, "taskExecution"):
+					response = taskExecutionResponse("task-1", "SUCCESS", "")
+				case strings.Contains(body.Query, "virtualHost(id"):
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							"virtualHost": map[string]interface{}{
+								"id":                   "vh-1",
+								"networkInterfaceList": []interface{}{},
+							},
+						},
+					}
+				default:
+					op := strings.Fields(body.Query[strings.Index(body.Query, "networkInterface"):])[0]
+					op = op[:strings.Index(op, "(")]
+					call := op
+					in := body.Variables.Input
+					for _, key := range []string{"networkInterface", "virtualHost", "network"} {
+						if v, ok := in[key]; ok {
+							call += " " + key + "=" + v.(string)
+						}
+					}
+					if _, ok := in["network"]; ok {
+						ipList, ok := in["ipList"]
+						if !ok {
+							ipList = []interface{}{}
+						}
+						call += fmt.Sprintf(" ipList=%v", ipList)
+					}
+					calls = append(calls, call)
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							op: map[string]interface{}{
+								"__typename": "TaskExecutionNode",
+								"id":         "task-1",
+							},
+						},
+					}
+				}
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Fatalf("encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			res := ResourceVirtualHost()
+			raw := map[string]interface{}{
+				"region":                 "FINLAND",
+				"customer_id":            "customer-1",
+				"project_id":             "project-1",
+				"hostname":               "app-1",
+				"domain_id":              "domain-1",
+				"cpu_count":              2,
+				"memory_size_gb":         8,
+				"tier_id":                "tier-1",
+				"template_id":            "template-1",
+				"note":                   "managed-by-terraform",
+				"data_protection_policy": "policy-1",
+				"interfaces": []interface{}{
+					map[string]interface{}{"network_id": "net-1"},
+					map[string]interface{}{"network_id": "net-2"},
+				},
+			}
+			oldData := schema.TestResourceDataRaw(t, res.Schema, raw)
+			oldData.SetId("vh-1")
+			_ = oldData.Set("interfaces", []interface{}{
+				map[string]interface{}{"id": "nic-1", "network_id": "net-1", "auto_assign_ip": true},
+				map[string]interface{}{"id": "nic-2", "network_id": "net-2", "auto_assign_ip": true},
+			})
+
+			raw["interfaces"] = tc.config
+			newData := resourceDataWithState(t, res, oldData.State(), raw)
+			newData.SetId("vh-1")
+
+			client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+			diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantError, diags)
+				}
+				if len(calls) > 0 {
+					t.Fatalf("expected no mutations, got %v", calls)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags[0].Summary)
+			}
+			if got, want := strings.Join(calls, "\n"), strings.Join(tc.calls, "\n"); got != want {
+				t.Fatalf("unexpected calls:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestResourceVirtualHostReadInterfacesDrift(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"virtualHost": map[string]interface{}{
+					"id": "vh-1",
+					"networkInterfaceList": []interface{}{
+						map[string]interface{}{
+							"id":             "nic-1",
+							"label":          "Network adapter 1",
+							"macAddress":     "00:50:56:aa:bb:cc",
+							"network":        map[string]interface{}{"id": "net-1"},
+							"ipv4Addresses":  []interface{}{map[string]interface{}{"ip": "192.0.2.10", "prefixlen": 24}},
+							"ipv6Addresses":  []interface{}{map[string]interface{}{"ip": "2001:db8::10", "prefixlen": 64}},
+							"startConnected": true,
+						},
+						map[string]interface{}{
+							"id":            "nic-2",
+							"network":       map[string]interface{}{"id": "net-9"},
+							"ipv4Addresses": []interface{}{map[string]interface{}{"ip": "198.51.100.7", "prefixlen": 24}},
+						},
+					},
+					"diskList": []interface{}{
+						map[string]interface{}{"id": "disk-0", "sizeGB": 40, "systemDisk": true, "tier": map[string]interface{}{"id": "tier-1"}},
+						map[string]interface{}{"id": "disk-1", "sizeGB": 100, "tier": map[string]interface{}{"id": "tier-2"}},
+					},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := ocpclient.New(server.URL, "token", true)
+	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, map[string]interface{}{
+		"interfaces": []interface{}{
+			map[string]interface{}{"network_id": "net-1", "auto_assign_ip": true},
+		},
+	})
+	data.SetId("vh-1")
+
+	diags := ResourceVirtualHostRead(context.Background(), data, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+
+	ifaces := data.Get("interfaces").([]interface{})
+	if len(ifaces) != 2 {
+		t.Fatalf("expected 2 interfaces, got %d", len(ifaces))
+	}
+	first := ifaces[0].(map[string]interface{})
+	if first["id"] != "nic-1" || first["auto_assign_ip"] != true || first["ip"] != "" {
+		t.Fatalf("unexpected first interface: %v", first)
+	}
+	if first["label"] != "Network adapter 1" || first["mac_address"] != "00:50:56:aa:bb:cc" || first["start_connected"] != true {
+		t.Fatalf("unexpected first interface details: %v", first)
+	}
+	if got := data.Get("interfaces.0.ipv6_addresses.0.address").(string); got != "2001:db8::10" {
+		t.Fatalf("expected ipv6 address 2001:db8::10, got %q", got)
+	}
+	if got := data.Get("interfaces.0.ipv6_addresses.0.prefix_length").(int); got != 64 {
+		t.Fatalf("expected ipv6 prefix length 64, got %d", got)
+	}
+	if got := data.Get("interfaces.1.ipv4_addresses.0.prefix_length").(int); got != 24 {
+		t.Fatalf("expected ipv4 prefix length 24, got %d", got)
+	}
+	if got := data.Get("primary_ip").(string); got != "192.0.2.10" {
+		t.Fatalf("expected primary_ip 192.0.2.10, got %q", got)
+	}
+
+	disks := data.Get("disk").([]interface{})
+	if len(disks) != 1 {
+		t.Fatalf("expected 1 data disk, got %d", len(disks))
+	}
+	if disk := disks[0].(map[string]interface{}); disk["id"] != "disk-1" || disk["size_gb"] != 100 || disk["tier_id"] != "tier-2" {
+		t.Fatalf("unexpected disk: %v", disk)
+	}
+	second := ifaces[1].(map[string]interface{})
+	if second["network_id"] != "net-9" || second["auto_assign_ip"] != true || second["ip"] != "" {
+		t.Fatalf("unexpected second interface: %v", second)
+	}
+}
+
+// TestResourceVirtualHostUpdateCoverage guards against attributes that show a diff,
+// report success on apply and then show the same diff again.
+func TestResourceVirtualHostUpdateCoverage(t *testing.T) {
+	// Terraform-only attributes that are never sent to the API after create.
+	local := map[string]bool{
+		"allow_resize_restart": true,
+		"deletion_protection":  true,
+		"wait_for_state":       true,
+	}
+
+	handled := map[string]bool{}
+	for _, step := range virtualHostUpdateSteps {
+		for _, key := range step.keys {
+			handled[key] = true
+		}
+	}
+
+	for key, s := range ResourceVirtualHost().Schema {
+		computedOnly := s.Computed && !s.Optional && !s.Required
+		if s.ForceNew || computedOnly || local[key] || handled[key] {
+			continue
+		}
+		t.Errorf("attribute %q is updatable but not handled by any update step; handle it or mark it ForceNew", key)
+	}
+}
+
+func TestResourceVirtualHostUpdateSettings(t *testing.T) {
+	var input map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Input map[string]interface{} `json:"input"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		var response map[string]interface{}
+		switch {
+		case strings.Contains(body.Query, "virtualHostUpdate("):
+			input = body.Variables.Input
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHostUpdate": map[string]interface{}{
+						"__typename": "VirtualHostNode",
+						"id":         "vh-1",
+					},
+				},
+			}
+		case strings.Contains(body.Query, "virtualHost(id"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHost": map[string]interface{}{
+						"id":                   "vh-1",
+						"note":                 "owned by team-b",
+						"dataProtectionPolicy": map[string]interface{}{"id": "policy-2"},
+						"networkInterfaceList": []interface{}{},
+					},
+				},
+			}
+		default:
+			t.Fatalf("unexpected query: %s", body.Query)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	res := ResourceVirtualHost()
+	raw := map[string]interface{}{
+		"region":                 "FINLAND",
+		"customer_id":            "customer-1",
+		"project_id":             "project-1",
+		"hostname":               "app-1",
+		"domain_id":              "domain-1",
+		"cpu_count":              2,
+		"memory_size_gb":         8,
+		"tier_id":                "tier-1",
+		"template_id":            "template-1",
+		"note":                   "owned by team-a",
+		"data_protection_policy": "policy-1",
+		"interfaces":             []interface{}{},
+	}
+	oldData := schema.TestResourceDataRaw(t, res.Schema, raw)
+	oldData.SetId("vh-1")
+
+	raw["note"] = "owned by team-b"
+	raw["data_protection_policy"] = "policy-2"
+	newData := resourceDataWithState(t, res, oldData.State(), raw)
+	newData.SetId("vh-1")
+
+	client := ocpclient.New(server.URL, "token", true)
+	diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+	if input["note"] != "owned by team-b" || input["dataProtectionPolicy"] != "policy-2" || input["virtualHost"] != "vh-1" {
+		t.Fatalf("unexpected virtualHostUpdate input: %v", input)
+	}
+}
+
+func TestResourceVirtualHostUpdateDisks(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    []interface{}
+		calls     []string
+		wantError string
+	}{
+		{
+			name: "grow, re-tier and attach",
+			config: []interface{}{
+				map[string]interface{}{"size_gb": 50, "tier_id": "tier-2"},
+				map[string]interface{}{"size_gb": 100, "tier_id": "tier-1"},
+				map[string]interface{}{"size_gb": 200},
+			},
+			calls: []string{
+				"diskUpdate disk=disk-1 sizeGB=50 tier=tier-2",
+				"diskCreate virtualHost=vh-1 sizeGB=200 tier=<nil>",
+			},
+		},
+		{
+			name: "detach",
+			config: []interface{}{
+				map[string]interface{}{"size_gb": 20, "tier_id": "tier-1"},
+			},
+			calls: []string{
+				"diskDelete disk=disk-2",
+			},
+		},
+		{
+			name: "shrink",
+			config: []interface{}{
+				map[string]interface{}{"size_gb": 10, "tier_id": "tier-1"},
+				map[string]interface{}{"size_gb": 100, "tier_id": "tier-1"},
+			},
+			wantError: "size_gb cannot be decreased from 20 to 10",
+		},
+		{
+			name: "remove first disk",
+			config: []interface{}{
+				map[string]interface{}{"size_gb": 100, "tier_id": "tier-1"},
+			},
+			wantError: "disk.0: disk blocks are matched by position",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query     string `json:"query"`
+					Variables struct {
+						Input map[string]interface{} `json:"input"`
+					} `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request: %v", err)
+				}
+
+				var response map[string]interface{}
+				switch {
+				case strings.Contains(body.Query, "taskExecution"):
+					response = taskExecutionResponse("task-1", "SUCCESS", "")
+				case strings.Contains(body.Query, "virtualHost(id"):
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							"virtualHost": map[string]interface{}{"id": "vh-1"},
+						},
+					}
+				default:
+					op := strings.Fields(body.Query[strings.Index(body.Query, "disk"):])[0]
+					op = op[:strings.Index(op, "(")]
+					in := body.Variables.Input
+					call := op
+					switch op {
+					case "diskCreate":
+						call += fmt.Sprintf(" virtualHost=%v sizeGB=%v tier=%v", in["virtualHost"], in["sizeGB"], in["tier"])
+					case "diskUpdate":
+						call += fmt.Sprintf(" disk=%v sizeGB=%v tier=%v", in["disk"], in["sizeGB"], in["tier"])
+					default:
+						call += fmt.Sprintf(" disk=%v", in["disk"])
+					}
+					calls = append(calls, call)
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							op: map[string]interface{}{
+								"__typename": "TaskExecutionNode",
+								"id":         "task-1",
+							},
+						},
+					}
+				}
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Fatalf("encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			res := ResourceVirtualHost()
+			raw := map[string]interface{}{
+				"region":                 "FINLAND",
+				"customer_id":            "customer-1",
+				"project_id":             "project-1",
+				"hostname":               "app-1",
+				"domain_id":              "domain-1",
+				"cpu_count":              2,
+				"memory_size_gb":         8,
+				"tier_id":                "tier-1",
+				"template_id":            "template-1",
+				"note":                   "managed-by-terraform",
+				"data_protection_policy": "policy-1",
+				"interfaces":             []interface{}{},
+				"disk": []interface{}{
+					map[string]interface{}{"size_gb": 20, "tier_id": "tier-1"},
+					map[string]interface{}{"size_gb": 100, "tier_id": "tier-1"},
+				},
+			}
+			oldData := schema.TestResourceDataRaw(t, res.Schema, raw)
+			oldData.SetId("vh-1")
+			_ = oldData.Set("disk", []interface{}{
+				map[string]interface{}{"id": "disk-1", "size_gb": 20, "tier_id": "tier-1"},
+				map[string]interface{}{"id": "disk-2", "size_gb": 100, "tier_id": "tier-1"},
+			})
+
+			raw["disk"] = tc.config
+			newData := resourceDataWithState(t, res, oldData.State(), raw)
+			newData.SetId("vh-1")
+
+			client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+			diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+			if tc.wantError != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantError, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags[0].Summary)
+			}
+			if got, want := strings.Join(calls, "\n"), strings.Join(tc.calls, "\n"); got != want {
+				t.Fatalf("unexpected calls:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestResourceVirtualHostUpdatePowerState(t *testing.T) {
+	var input map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Input map[string]interface{} `json:"input"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		var response map[string]interface{}
+		switch {
+		case strings.Contains(body.Query, "virtualHostSetPowerState"):
+			input = body.Variables.Input
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHostSetPowerState": map[string]interface{}{
+						"__typename": "TaskExecutionNode",
+						"id":         "task-1",
+					},
+				},
+			}
+		case strings.Contains(body.Query, "taskExecution"):
+			response = taskExecutionResponse("task-1", "SUCCESS", "")
+		case strings.Contains(body.Query, "virtualHost(id"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHost": map[string]interface{}{
+						"id":         "vh-1",
+						"powerState": "POWERED_OFF",
+					},
+				},
+			}
+		default:
+			t.Fatalf("unexpected query: %s", body.Query)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	res := ResourceVirtualHost()
+	raw := map[string]interface{}{
+		"region":                 "FINLAND",
+		"customer_id":            "customer-1",
+		"project_id":             "project-1",
+		"hostname":               "app-1",
+		"domain_id":              "domain-1",
+		"cpu_count":              2,
+		"memory_size_gb":         8,
+		"tier_id":                "tier-1",
+		"template_id":            "template-1",
+		"note":                   "managed-by-terraform",
+		"data_protection_policy": "policy-1",
+		"interfaces":             []interface{}{},
+		"power_state":            "on",
+	}
+	oldData := schema.TestResourceDataRaw(t, res.Schema, raw)
+	oldData.SetId("vh-1")
+
+	raw["power_state"] = "off"
+	newData := resourceDataWithState(t, res, oldData.State(), raw)
+	newData.SetId("vh-1")
+
+	client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+	diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+	if input["virtualHost"] != "vh-1" || input["powerState"] != "POWERED_OFF" {
+		t.Fatalf("unexpected virtualHostSetPowerState input: %v", input)
+	}
+	if got := newData.Get("power_state").(string); got != "off" {
+		t.Fatalf("expected power_state off, got %q", got)
+	}
+}
+
+func TestResourceVirtualHostUpdateTierOptions(t *testing.T) {
+	testCases := []struct {
+		name      string
+		priorIOPS int
+		overrides map[string]interface{}
+		wantInput string
+	}{
+		{
+			name:      "extended tier with target iops",
+			overrides: map[string]interface{}{"tier_extended": true, "target_iops": 5000},
+			wantInput: "tier=tier-1 isExtended=true targetIops=5000",
+		},
+		{
+			name:      "tier change keeps read-back target iops out",
+			priorIOPS: 3000,
+			overrides: map[string]interface{}{"tier_id": "tier-2"},
+			wantInput: "tier=tier-2 isExtended=false targetIops=<nil>",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var tierInput map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query     string `json:"query"`
+					Variables struct {
+						Input map[string]interface{} `json:"input"`
+					} `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request: %v", err)
+				}
+
+				var response map[string]interface{}
+				switch {
+				case strings.Contains(body.Query, "virtualHostUpdateTier"):
+					tierInput = body.Variables.Input
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							"virtualHostUpdateTier": map[string]interface{}{
+								"__typename": "TaskExecutionNode",
+								"id":         "task-1",
+							},
+						},
+					}
+				case strings.Contains(body.Query, "taskExecution"):
+					response = taskExecutionResponse("task-1", "SUCCESS", "")
+				case strings.Contains(body.Query, "virtualHost(id"):
+					response = map[string]interface{}{
+						"data": map[string]interface{}{
+							"virtualHost": map[string]interface{}{
+								"id":             "vh-1",
+								"state":          "ACTIVE",
+								"cpuCount":       2,
+								"coresPerSocket": 1,
+								"memorySizeMB":   8192,
+								"tier":           map[string]interface{}{"id": tierInput["tier"]},
+								"isExtended":     tierInput["isExtended"],
+								"targetIops":     5000,
+							},
+						},
+					}
+				default:
+					t.Fatalf("unexpected query: %s", body.Query)
+				}
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					t.Fatalf("encode response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			res := ResourceVirtualHost()
+			oldData := schema.TestResourceDataRaw(t, res.Schema, planTestConfig(nil))
+			oldData.SetId("vh-1")
+			if tc.priorIOPS != 0 {
+				_ = oldData.Set("target_iops", tc.priorIOPS)
+			}
+
+			newData := resourceDataWithState(t, res, oldData.State(), planTestConfig(tc.overrides))
+			newData.SetId("vh-1")
+
+			client := ocpclient.New(server.URL, "token", true, ocpclient.WithPollInterval(time.Millisecond))
+			diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags[0].Summary)
+			}
+
+			if got := fmt.Sprintf("tier=%v isExtended=%v targetIops=%v", tierInput["tier"], tierInput["isExtended"], tierInput["targetIops"]); got != tc.wantInput {
+				t.Fatalf("unexpected tier change input: %s, want %s", got, tc.wantInput)
+			}
+			if newData.Get("target_iops").(int) != 5000 {
+				t.Fatalf("expected target_iops to be read back, got %v", newData.Get("target_iops"))
+			}
+		})
+	}
+}
+
+func TestResourceVirtualHostCreatePlacement(t *testing.T) {
+	var input map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Input map[string]interface{} `json:"input"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if !strings.Contains(body.Query, "virtualHostCreate") {
+			t.Fatalf("unexpected query: %s", body.Query)
+		}
+		input = body.Variables.Input
+
+		response := map[string]interface{}{
+			"data": map[string]interface{}{
+				"virtualHostCreate": map[string]interface{}{
+					"__typename": "VirtualHostCreated",
+					"virtualHost": map[string]interface{}{
+						"id":    "vh-1",
+						"state": "ACTIVE",
+					},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	client := ocpclient.New(server.URL, "token", true)
+	data := schema.TestResourceDataRaw(t, ResourceVirtualHost().Schema, planTestConfig(map[string]interface{}{
+		"anti_affinity":     "web-tier",
+		"business_service":  "BS-1234",
+		"dedicated_cluster": "cluster-a",
+	}))
+
+	diags := ResourceVirtualHostCreate(context.Background(), data, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+
+	if input["antiAffinity"] != "web-tier" || input["businessService"] != "BS-1234" || input["dedicatedCluster"] != "cluster-a" {
+		t.Fatalf("expected placement options in create input, got %v", input)
+	}
+	for _, field := range []string{"dedicatedDrCluster", "clusterType", "version"} {
+		if _, ok := input[field]; ok {
+			t.Fatalf("expected unset %s to be omitted, got %v", field, input[field])
+		}
+	}
+}
+
+func TestResourceVirtualHostUpdatePlacement(t *testing.T) {
+	var input map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Input map[string]interface{} `json:"input"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		var response map[string]interface{}
+		switch {
+		case strings.Contains(body.Query, "virtualHostUpdate("):
+			input = body.Variables.Input
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHostUpdate": map[string]interface{}{
+						"__typename": "VirtualHostNode",
+						"id":         "vh-1",
+					},
+				},
+			}
+		case strings.Contains(body.Query, "virtualHost(id"):
+			response = map[string]interface{}{
+				"data": map[string]interface{}{
+					"virtualHost": map[string]interface{}{
+						"id":                 "vh-1",
+						"antiAffinity":       "db-tier",
+						"businessService":    "BS-1234",
+						"dedicatedCluster":   map[string]interface{}{"id": "cluster-a"},
+						"dedicatedDrCluster": map[string]interface{}{"id": "cluster-dr"},
+						"clusterType":        "PRIMARY",
+						"version":            "2",
+					},
+				},
+			}
+		default:
+			t.Fatalf("unexpected query: %s", body.Query)
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	res := ResourceVirtualHost()
+	oldData := schema.TestResourceDataRaw(t, res.Schema, planTestConfig(map[string]interface{}{
+		"anti_affinity":    "web-tier",
+		"business_service": "BS-1234",
+	}))
+	oldData.SetId("vh-1")
+
+	newData := resourceDataWithState(t, res, oldData.State(), planTestConfig(map[string]interface{}{
+		"anti_affinity":    "db-tier",
+		"business_service": "BS-1234",
+	}))
+	newData.SetId("vh-1")
+
+	client := ocpclient.New(server.URL, "token", true)
+	diags := ResourceVirtualHostUpdate(context.Background(), newData, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags[0].Summary)
+	}
+
+	if input["antiAffinity"] != "db-tier" {
+		t.Fatalf("expected antiAffinity update, got %v", input)
+	}
+	if _, ok := input["businessService"]; ok {
+		t.Fatalf("expected unchanged businessService to be omitted, got %v", input)
+	}
+	if got := newData.Get("dedicated_dr_cluster").(string); got != "cluster-dr" {
+		t.Fatalf("expected dedicated_dr_cluster to be read back, got %q", got)
+	}
+	if got := newData.Get("version").(string); got != "2" {
+		t.Fatalf("expected version to be read back, got %q", got)
 	}
 }
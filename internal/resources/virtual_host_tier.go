@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// tierExtendedSchema is the tier_extended attribute shared by ocp_virtual_host and ocp_virtual_host_immutable.
+func tierExtendedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: "Use the extended variant of the storage tier, which allows a guaranteed `target_iops`. The tier must support it.",
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+// targetIOPSSchema is the target_iops attribute shared by ocp_virtual_host and ocp_virtual_host_immutable.
+func targetIOPSSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Description:  "Guaranteed IOPS of the virtual host's storage. Requires `tier_extended` and must be within the limits of the tier. When not set, the tier default is used and read back.",
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+	}
+}
+
+// addTierOptions adds isExtended and targetIops to a create or tier change input.
+// targetIops is only sent for an extended tier and when set in the configuration: state holds
+// the tier default read back from the API, which must not be replayed to another tier.
+func addTierOptions(d *schema.ResourceData, input map[string]interface{}) {
+	extended := d.Get("tier_extended").(bool)
+	input["isExtended"] = extended
+	if !extended {
+		return
+	}
+	if raw := d.GetRawConfig(); !raw.IsNull() && !raw.GetAttr("target_iops").IsNull() {
+		input["targetIops"] = d.Get("target_iops").(int)
+	}
+}
+
+// flattenTargetIOPS maps a missing API targetIops to 0, which leaves target_iops unset.
+func flattenTargetIOPS(iops *int) int {
+	if iops == nil {
+		return 0
+	}
+	return *iops
+}
+
+// plannedTargetIOPS returns target_iops when it is known and set on create or planned to change.
+// Unchanged values are ignored, as the API reports the tier default for virtual hosts that never
+// set it.
+func plannedTargetIOPS(d *schema.ResourceDiff) (int, bool) {
+	iops, ok := knownInt(d, "target_iops")
+	if !ok || iops == 0 || (d.Id() != "" && !d.HasChange("target_iops")) {
+		return 0, false
+	}
+	return iops, true
+}
+
+// validateTierOptions reports target_iops without tier_extended. Unlike the tier limits it needs
+// no API lookup.
+func validateTierOptions(d *schema.ResourceDiff) []error {
+	if _, ok := plannedTargetIOPS(d); !ok || !d.NewValueKnown("tier_extended") || d.Get("tier_extended").(bool) {
+		return nil
+	}
+	return []error{fmt.Errorf("target_iops requires tier_extended = true")}
+}
+
+// check reports tier options the tier does not allow.
+func (t *tierConstraints) check(d *schema.ResourceDiff) []error {
+	var errs []error
+
+	if d.NewValueKnown("tier_extended") && d.Get("tier_extended").(bool) && !t.SupportsExtended {
+		errs = append(errs, fmt.Errorf("tier_extended: tier %q does not support the extended variant", t.Name))
+	}
+
+	iops, ok := plannedTargetIOPS(d)
+	if !ok {
+		return errs
+	}
+	if t.MinIOPS != nil && iops < *t.MinIOPS {
+		errs = append(errs, fmt.Errorf("target_iops: %d is below the minimum of %d for tier %q", iops, *t.MinIOPS, t.Name))
+	}
+	if t.MaxIOPS != nil && iops > *t.MaxIOPS {
+		errs = append(errs, fmt.Errorf("target_iops: %d exceeds the maximum of %d for tier %q", iops, *t.MaxIOPS, t.Name))
+	}
+
+	return errs
+}
@@ -6,9 +6,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
 	"github.com/davidhrbac/terraform-provider-ocp/internal/datasources"
@@ -40,9 +47,93 @@ func Provider() *schema.Provider {
 			},
 			"insecure_skip_verify": {
 				Type:        schema.TypeBool,
-				Description: "Insecure skip verify.",
+				Description: "Skip TLS certificate verification. Defaults to `true` unless `ca_cert_file`, `ca_cert_pem` or `client_cert` is set, in which case certificates are verified. Not recommended; trust the portal CA with `ca_cert_file` or `ca_cert_pem` instead.",
 				Optional:    true,
-				Default:     true,
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Description: "Path to a PEM-encoded CA bundle trusted in addition to the system roots. Can also be set with the OCP_CA_CERT_FILE environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_CA_CERT_FILE", nil),
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Description: "PEM-encoded CA bundle trusted in addition to the system roots. Can also be set with the OCP_CA_CERT_PEM environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_CA_CERT_PEM", nil),
+			},
+			"client_cert": {
+				Type:        schema.TypeString,
+				Description: "PEM-encoded client certificate for mutual TLS, or a path to a file containing it. Can also be set with the OCP_CLIENT_CERT environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_CLIENT_CERT", nil),
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Description: "PEM-encoded private key of the client certificate, or a path to a file containing it. Can also be set with the OCP_CLIENT_KEY environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_CLIENT_KEY", nil),
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Description: "URL of the HTTP proxy used to reach the API, e.g. `http://proxy.example.com:3128`. Can also be set with the OCP_PROXY_URL environment variable. When unset, the standard HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment variables apply.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_PROXY_URL", nil),
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Description: "Comma-separated hosts, domain suffixes, IPs or CIDRs reached without `proxy_url`. Can also be set with the OCP_NO_PROXY environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_NO_PROXY", nil),
+			},
+			"proxy_username": {
+				Type:        schema.TypeString,
+				Description: "Username for an authenticated `proxy_url`. Can also be set with the OCP_PROXY_USERNAME environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_PROXY_USERNAME", nil),
+			},
+			"proxy_password": {
+				Type:        schema.TypeString,
+				Description: "Password for an authenticated `proxy_url`. Can also be set with the OCP_PROXY_PASSWORD environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("OCP_PROXY_PASSWORD", nil),
+			},
+			"max_requests_per_second": {
+				Type:             schema.TypeFloat,
+				Description:      "Client-side limit of API requests per second, including retries. `0` disables the limit.",
+				Optional:         true,
+				Default:          ocpclient.DefaultMaxRequestsPerSecond,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.FloatAtLeast(0)),
+			},
+			"max_concurrent_mutations": {
+				Type:             schema.TypeInt,
+				Description:      "Maximum number of mutations in flight at once, regardless of Terraform parallelism. `0` disables the cap.",
+				Optional:         true,
+				Default:          ocpclient.DefaultMaxConcurrentMutations,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"request_timeout": {
+				Type:             schema.TypeString,
+				Description:      "Maximum duration of a single API request, as a Go duration string (e.g. `30s`, `2m`). `0s` disables the timeout.",
+				Optional:         true,
+				Default:          ocpclient.DefaultRequestTimeout.String(),
+				ValidateDiagFunc: validateDuration,
+			},
+			"max_retries": {
+				Type:             schema.TypeInt,
+				Description:      "Maximum number of retries for transient API failures. Queries are retried on network errors and 429/502/503/504 responses; mutations only when the request never reached the server. Certificate verification failures and unknown hosts are not retried.",
+				Optional:         true,
+				Default:          ocpclient.DefaultMaxRetries,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"retry_max_wait": {
+				Type:             schema.TypeString,
+				Description:      "Maximum delay between retries, as a Go duration string. Also caps server-provided `Retry-After` values.",
+				Optional:         true,
+				Default:          ocpclient.DefaultRetryMaxWait.String(),
+				ValidateDiagFunc: validateDuration,
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
@@ -68,7 +159,14 @@ func Provider() *schema.Provider {
 		ConfigureContextFunc: func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 			endpoint := d.Get("endpoint").(string)
 			token := d.Get("token").(string)
-			insecure := d.Get("insecure_skip_verify").(bool)
+			requestTimeout, err := time.ParseDuration(d.Get("request_timeout").(string))
+			if err != nil {
+				return nil, diag.Errorf("invalid request_timeout: %s", err)
+			}
+			retryMaxWait, err := time.ParseDuration(d.Get("retry_max_wait").(string))
+			if err != nil {
+				return nil, diag.Errorf("invalid retry_max_wait: %s", err)
+			}
 
 			var diags diag.Diagnostics
 
@@ -85,9 +183,129 @@ func Provider() *schema.Provider {
 				return nil, diags
 			}
 
-			client := ocpclient.New(endpoint, token, insecure)
+			insecure, err := providerInsecure(d)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			tlsConfig, err := providerTLSConfig(d, insecure)
+			if err != nil {
+				return nil, diag.Errorf("failed to configure TLS: %s", err)
+			}
+			proxyFunc, err := ocpclient.NewProxyFunc(ocpclient.ProxyOptions{
+				URL:      d.Get("proxy_url").(string),
+				NoProxy:  d.Get("no_proxy").(string),
+				Username: d.Get("proxy_username").(string),
+				Password: d.Get("proxy_password").(string),
+			})
+			if err != nil {
+				return nil, diag.Errorf("failed to configure proxy: %s", err)
+			}
+			if insecure {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "TLS certificate verification is disabled",
+					Detail: "`insecure_skip_verify` is enabled, so the identity of the OCP API is not verified. " +
+						"Set `insecure_skip_verify = false` and provide the portal CA with `ca_cert_file` or `ca_cert_pem`.",
+				})
+			}
+
+			client := ocpclient.New(endpoint, token, insecure,
+				ocpclient.WithTLSConfig(tlsConfig),
+				ocpclient.WithProxyFunc(proxyFunc),
+				ocpclient.WithRequestTimeout(requestTimeout),
+				ocpclient.WithRateLimit(d.Get("max_requests_per_second").(float64)),
+				ocpclient.WithMaxConcurrentMutations(d.Get("max_concurrent_mutations").(int)),
+				ocpclient.WithRetryPolicy(ocpclient.RetryPolicy{
+					MaxRetries: d.Get("max_retries").(int),
+					MinWait:    min(ocpclient.DefaultRetryMinWait, retryMaxWait),
+					MaxWait:    retryMaxWait,
+				}),
+			)
 
 			return client, diags
 		},
 	}
 }
+
+// providerInsecure resolves insecure_skip_verify. Verification stays off by default for
+// existing configurations, but a configured CA bundle or client certificate turns it on
+// unless the attribute is set explicitly; setting it to true alongside them is rejected
+// because the CA would be silently ignored.
+func providerInsecure(d *schema.ResourceData) (bool, error) {
+	tlsConfigured := d.Get("ca_cert_file").(string) != "" ||
+		d.Get("ca_cert_pem").(string) != "" ||
+		d.Get("client_cert").(string) != ""
+
+	raw := d.GetRawConfig()
+	if raw.IsNull() || raw.GetAttr("insecure_skip_verify").IsNull() {
+		return !tlsConfigured, nil
+	}
+
+	insecure := d.Get("insecure_skip_verify").(bool)
+	if insecure && tlsConfigured {
+		return false, fmt.Errorf("insecure_skip_verify = true conflicts with ca_cert_file, ca_cert_pem and client_cert: " +
+			"remove insecure_skip_verify to verify the OCP API against the configured CA")
+	}
+	return insecure, nil
+}
+
+// providerTLSConfig builds the client TLS configuration from the provider settings.
+func providerTLSConfig(d *schema.ResourceData, insecure bool) (*tls.Config, error) {
+	opts := ocpclient.TLSOptions{
+		Insecure: insecure,
+	}
+
+	if path := d.Get("ca_cert_file").(string); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read ca_cert_file: %w", err)
+		}
+		opts.CACertPEM = append(opts.CACertPEM, pem...)
+	}
+	if pem := d.Get("ca_cert_pem").(string); pem != "" {
+		opts.CACertPEM = append(opts.CACertPEM, []byte("\n"+pem)...)
+	}
+
+	var err error
+	if opts.ClientCertPEM, err = pemOrFile(d.Get("client_cert").(string)); err != nil {
+		return nil, fmt.Errorf("read client_cert: %w", err)
+	}
+	if opts.ClientKeyPEM, err = pemOrFile(d.Get("client_key").(string)); err != nil {
+		return nil, fmt.Errorf("read client_key: %w", err)
+	}
+
+	return ocpclient.NewTLSConfig(opts)
+}
+
+// pemOrFile returns value itself when it holds PEM data, or the contents of the file it points to.
+func pemOrFile(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
+// validateDuration checks that a string attribute holds a non-negative Go duration.
+func validateDuration(v interface{}, path cty.Path) diag.Diagnostics {
+	d, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        fmt.Sprintf("%q is not a valid duration: %s", v, err),
+			AttributePath: path,
+		}}
+	}
+	if d < 0 {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        fmt.Sprintf("%q must not be negative", v),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
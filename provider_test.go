@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	ocpclient "github.com/davidhrbac/terraform-provider-ocp/internal/client"
+)
+
+// providerConfig builds a provider configuration that also carries the raw config value,
+// as Terraform does, so unset attributes can be told apart from zero values.
+func providerConfig(t *testing.T, p *schema.Provider, raw map[string]interface{}) *terraform.ResourceConfig {
+	t.Helper()
+
+	block := schema.InternalMap(p.Schema).CoreConfigSchema()
+	attrs := make(map[string]cty.Value, len(block.Attributes))
+	for name, attr := range block.Attributes {
+		switch v := raw[name].(type) {
+		case string:
+			attrs[name] = cty.StringVal(v)
+		case bool:
+			attrs[name] = cty.BoolVal(v)
+		case nil:
+			attrs[name] = cty.NullVal(attr.Type)
+		default:
+			t.Fatalf("unsupported value %T for %s", v, name)
+		}
+	}
+	val := cty.ObjectVal(attrs)
+	config := terraform.NewResourceConfigShimmed(val, block)
+	config.CtyValue = val
+	return config
+}
+
+// selfSignedCAPEM returns a CA certificate unrelated to the httptest server certificate.
+func selfSignedCAPEM(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestProviderTLSVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer server.Close()
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	otherCAPEM := selfSignedCAPEM(t)
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantErr     bool
+		wantWarning bool
+		wantDoErr   bool
+	}{
+		{
+			name:        "no CA keeps verification off",
+			config:      map[string]interface{}{},
+			wantWarning: true,
+		},
+		{
+			name:   "CA enables verification",
+			config: map[string]interface{}{"ca_cert_pem": caPEM},
+		},
+		{
+			name:      "CA is verified",
+			config:    map[string]interface{}{"ca_cert_pem": otherCAPEM},
+			wantDoErr: true,
+		},
+		{
+			name:   "explicit false with CA",
+			config: map[string]interface{}{"ca_cert_pem": caPEM, "insecure_skip_verify": false},
+		},
+		{
+			name:    "explicit true with CA is rejected",
+			config:  map[string]interface{}{"ca_cert_pem": caPEM, "insecure_skip_verify": true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"endpoint": server.URL,
+				"token":    "token",
+			}
+			for k, v := range tt.config {
+				raw[k] = v
+			}
+
+			p := Provider()
+			diags := p.Configure(context.Background(), providerConfig(t, p, raw))
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("Configure() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := len(diags) > 0; got != tt.wantWarning {
+				t.Fatalf("Configure() warning = %v, want %v (%v)", got, tt.wantWarning, diags)
+			}
+
+			var out struct {
+				OK bool `json:"ok"`
+			}
+			err := p.Meta().(*ocpclient.Client).Do(`query { ok }`, nil, &out)
+			if (err != nil) != tt.wantDoErr {
+				t.Fatalf("Do() error = %v, wantDoErr %v", err, tt.wantDoErr)
+			}
+		})
+	}
+}